@@ -0,0 +1,121 @@
+package bmcapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Batch is a sequence of operations built with BMCAPI.Batch and run in order with Run, e.g.
+//
+//	err := bmc.Batch().PowerOff(0).Wait(2 * time.Second).PowerOn(0).UART(0, "boot").Run(ctx)
+//
+// Each step is one of the BMCAPI's methods. A Batch can be run more than once.
+type Batch struct {
+	bmc   *BMCAPI
+	steps []batchStep
+}
+
+// batchStep is a named step of a Batch.
+type batchStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Batch returns an empty Batch of operations on b.
+func (b *BMCAPI) Batch() *Batch {
+	return &Batch{bmc: b}
+}
+
+// Step adds a custom step, so batches can include operations that have no method of their own.
+func (batch *Batch) Step(name string, run func(ctx context.Context) error) *Batch {
+	batch.steps = append(batch.steps, batchStep{name: name, run: run})
+	return batch
+}
+
+// PowerOn adds a step that powers on the specified node (0-3) with SetNodePower.
+func (batch *Batch) PowerOn(node int) *Batch {
+	return batch.setResultStep(fmt.Sprintf("power on node %d", node), func() (SetResult, error) {
+		return batch.bmc.SetNodePower(node, PowerOn)
+	})
+}
+
+// PowerOff adds a step that powers off the specified node (0-3) with SetNodePower.
+func (batch *Batch) PowerOff(node int) *Batch {
+	return batch.setResultStep(fmt.Sprintf("power off node %d", node), func() (SetResult, error) {
+		return batch.bmc.SetNodePower(node, PowerOff)
+	})
+}
+
+// Reset adds a step that resets the specified node (0-3) with ResetNodeWithType.
+func (batch *Batch) Reset(node int, resetType ResetType) *Batch {
+	return batch.setResultStep(fmt.Sprintf("%s reset node %d", resetType, node), func() (SetResult, error) {
+		return batch.bmc.ResetNodeWithType(node, resetType)
+	})
+}
+
+// USBBoot adds a step that makes the specified node (0-3) boot from USB with USBBootResult.
+func (batch *Batch) USBBoot(node int) *Batch {
+	return batch.setResultStep(fmt.Sprintf("usb boot node %d", node), func() (SetResult, error) {
+		return batch.bmc.USBBootResult(node)
+	})
+}
+
+// ClearUSBBoot adds a step that clears the USB boot flag of the specified node (0-3) with ClearUSBBootResult.
+func (batch *Batch) ClearUSBBoot(node int) *Batch {
+	return batch.setResultStep(fmt.Sprintf("clear usb boot node %d", node), func() (SetResult, error) {
+		return batch.bmc.ClearUSBBootResult(node)
+	})
+}
+
+// UART adds a step that writes cmd to the serial console of the specified node (0-3) with SetUARTResult.
+func (batch *Batch) UART(node int, cmd string) *Batch {
+	return batch.setResultStep(fmt.Sprintf("uart node %d", node), func() (SetResult, error) {
+		return batch.bmc.SetUARTResult(node, cmd)
+	})
+}
+
+// WaitForPower adds a step that waits until the specified node (0-3) is on (want true) or off, with WaitForNodePower.
+func (batch *Batch) WaitForPower(node int, want bool) *Batch {
+	return batch.Step(fmt.Sprintf("wait for node %d to power %s", node, powerStateName(want)), func(ctx context.Context) error {
+		return batch.bmc.WaitForNodePower(ctx, node, want, 0)
+	})
+}
+
+// Wait adds a step that pauses for d, or until the context given to Run is done.
+func (batch *Batch) Wait(d time.Duration) *Batch {
+	return batch.Step(fmt.Sprintf("wait %v", d), func(ctx context.Context) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	})
+}
+
+// setResultStep adds a step for a method returning a SetResult, which fails unless the firmware reported success.
+func (batch *Batch) setResultStep(name string, run func() (SetResult, error)) *Batch {
+	return batch.Step(name, func(context.Context) error {
+		_, err := run()
+		return err
+	})
+}
+
+// Run runs the steps in order and stops at the first one that fails, returning a *BatchError for it.
+// A step is not started once ctx is done.
+func (batch *Batch) Run(ctx context.Context) error {
+	for i, step := range batch.steps {
+		err := ctx.Err()
+		if err == nil {
+			err = step.run(ctx)
+		}
+		if err != nil {
+			return &BatchError{Step: i, Name: step.name, Err: err}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	var requests []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.URL.RawQuery)
+		if req.URL.Query().Get("type") == "usb_boot" {
+			return mockResponse(http.StatusOK, `{"response":[{"result":"busy"}]}`), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	batch := bmc.Batch().PowerOff(0).Wait(time.Millisecond).PowerOn(0).UART(0, "boot")
+	if err := batch.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"opt=set&type=power&node1=0", "opt=set&type=power&node1=1", "opt=set&type=uart&node=0&cmd=boot"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	// The batch stops at the failed step and reports it
+	requests = nil
+	err := bmc.Batch().PowerOn(1).USBBoot(1).PowerOn(2).Run(context.Background())
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Step != 1 || batchErr.Name != "usb boot node 1" {
+		t.Fatalf("Run() error = %v, want a BatchError for step 1", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(requests) != 2 {
+		t.Errorf("Run() error = %v after %d requests, want the APIError of step 1 and no further request", err, len(requests))
+	}
+
+	// Waits end with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	custom := false
+	err = bmc.Batch().Wait(time.Hour).Step("custom", func(context.Context) error { custom = true; return nil }).Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || custom {
+		t.Errorf("Run() error = %v, custom step ran %v, want context.DeadlineExceeded before the custom step", err, custom)
+	}
+}
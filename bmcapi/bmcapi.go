@@ -1,18 +1,32 @@
 package bmcapi
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
 	// BMCAPIURL is the default base URL for the Turing PI 2
 	tpiDefaultURL = "https://turingpi.local"
+
+	// defaultAPIPrefix is the path the firmware serves its API under
+	defaultAPIPrefix = "/api/bmc"
 )
 
 type bmcApiAuth struct {
@@ -23,12 +37,70 @@ type bmcApiAuth struct {
 	Password    string `json:"password"` // Password for basic auth
 }
 
+// bmcAuthRequest is the body sent to the authenticate endpoint for bearer auth.
+type bmcAuthRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 // BMCAPI is a struct that holds the base URL and HTTP client for making API requests.
+//
+// A BMCAPI is safe for concurrent use by multiple goroutines once NewBMCAPI returned it, including while it
+// authenticates lazily, re-establishes a rejected session, fails over to a fallback URL or has its credentials
+// replaced with UpdateCredentials. The exported fields must not be changed once the BMCAPI is in use.
+// Concurrent calls are not coordinated beyond that: two goroutines powering the same node on and off race
+// at the BMC as they would with separate clients. Only identical reads made at the same time, such as a
+// dashboard calling GetPower from many goroutines, are combined into a single request to the BMC.
 type BMCAPI struct {
 	auth     *bmcApiAuth
 	BaseURL  string
 	Client   *http.Client
 	AuthType string
+
+	logger *slog.Logger
+	dryRun *slog.Logger
+
+	rebootCommand   string
+	shutdownTimeout time.Duration
+	powerCycleDelay time.Duration
+	lazyAuth        bool
+	timeouts        Timeouts
+	bearerToken     string
+	jitter          *pollJitter
+	reauth          *reauthBackoff
+	protectedNodes  map[int]bool
+	idempotencyKeys bool
+	strictTLS       bool
+	cache           *responseCache
+	flashRetries    int
+	defaultNode     int
+	hasDefaultNode  bool
+	forceHTTPS      bool
+	sdCardThreshold float64
+
+	// apiPrefix replaces defaultAPIPrefix in endpoints when customAPIPrefix is set
+	apiPrefix       string
+	customAPIPrefix bool
+
+	primaryURL   string
+	fallbackURLs []string
+
+	defaultHeaders       http.Header
+	requestInterceptors  []func(*http.Request) error
+	responseInterceptors []func(*http.Response, time.Duration, error)
+
+	// lastContact is the time of the last successful API call in Unix nanoseconds, 0 before the first
+	lastContact atomic.Int64
+
+	// authMu serializes the lazy first authentication
+	authMu sync.Mutex
+
+	// flights shares identical concurrent reads
+	flights flightGroup
+
+	// mu guards the client state below that can change after construction, as well as auth, reauth and BaseURL
+	mu        sync.RWMutex
+	nodeNames map[int]string
 }
 
 // bmcResultAPIResponse is a struct that represents the response from the BMC API for a single result.
@@ -39,52 +111,226 @@ type bmcResultAPIResponse struct {
 	} `json:"response"`
 }
 
+// SetResult is the result a set operation reported, which is "ok" on success.
+// Set operations that the firmware answers with another result return an *APIError instead.
+type SetResult struct {
+	Raw string
+}
+
+// Ok reports whether the firmware reported success.
+func (r SetResult) Ok() bool {
+	return r.Raw == "ok"
+}
+
+// String returns the result as reported by the firmware.
+func (r SetResult) String() string {
+	return r.Raw
+}
+
+// resultString converts the return values of a SetResult method to those of its deprecated *string counterpart.
+func resultString(result SetResult, err error) (*string, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &result.Raw, nil
+}
+
 // bmcObjectAPIResponse is a struct that represents the response from the BMC API for an object result.
 // It expects the response to be in the format {"response":[{"result":[{<resultobject>}] }]}
 type bmcObjectAPIResponse struct {
 	Response []struct {
-		Result []map[string]string `json:"result"`
+		Result []map[string]json.RawMessage `json:"result"`
 	} `json:"response"`
 }
 
 type bmcOther struct {
-	API          string
-	BuildVersion string
-	Buildroot    string
-	Buildtime    string
-	IP           string
-	MAC          string
-	Version      string
+	API          string `json:"api"`
+	BuildVersion string `json:"build_version"`
+	Buildroot    string `json:"buildroot"`
+	Buildtime    string `json:"buildtime"`
+	IP           string `json:"ip"`
+	MAC          string `json:"mac"`
+	Version      string `json:"version"`
+}
+
+// unknownValue is what the firmware reports for values it could not determine, such as the IP and MAC in Other.
+const unknownValue = "Unknown"
+
+// IPAddress returns IP parsed as a net.IP. ok is false when the firmware reports the IP as "Unknown" or
+// not at all; an IP that is set but cannot be parsed is an error.
+func (o *bmcOther) IPAddress() (ip net.IP, ok bool, err error) {
+	if o.IP == "" || o.IP == unknownValue {
+		return nil, false, nil
+	}
+
+	ip = net.ParseIP(strings.TrimSpace(o.IP))
+	if ip == nil {
+		return nil, false, fmt.Errorf("invalid IP address %q", o.IP)
+	}
+
+	return ip, true, nil
+}
+
+// HardwareAddr returns MAC parsed as a net.HardwareAddr. ok is false when the firmware reports the MAC as "Unknown"
+// or not at all; a MAC that is set but cannot be parsed is an error.
+func (o *bmcOther) HardwareAddr() (mac net.HardwareAddr, ok bool, err error) {
+	if o.MAC == "" || o.MAC == unknownValue {
+		return nil, false, nil
+	}
+
+	mac, err = net.ParseMAC(strings.TrimSpace(o.MAC))
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid MAC address %q: %w", o.MAC, err)
+	}
+
+	return mac, true, nil
 }
 
 // NewBMCAPI creates a new instance of BMCAPI with the given base URL and HTTP client.
 // Creates and uses the custom bmcOtherResponse struct to parse the response from the BMC API.
 // It returns a bmcOther struct or an error if the authentication fails or if the request cannot be made.
-func NewBMCAPI(baseURL, authType, username, password string, client *http.Client) (*BMCAPI, error) {
+// Options are applied before authenticating, so they also affect the authentication request.
+// If client is nil, the client returned by NewInsecureClient is used, which does not verify the
+// BMC's self-signed certificate. When the BMC has a trusted certificate, e.g. behind a TLS terminating
+// proxy, pass &http.Client{} or use WithStrictTLS so the certificate is verified. A client that verifies
+// certificates of a .local BMC is logged as a warning if a logger is configured, unless WithStrictTLS is used.
+// Redirects, e.g. from an http base URL to https, are followed by the SDK with the request's credentials
+// and body intact, but only to the same host; the client's CheckRedirect is not used.
+func NewBMCAPI(baseURL, authType, username, password string, client *http.Client, opts ...Option) (*BMCAPI, error) {
 
 	// Try default Turing Pi 2 URL if baseURL is empty
 	if baseURL == "" {
 		baseURL = tpiDefaultURL
 	}
 
-	var authResponse bmcApiAuth
+	baseURL, err := normalizeBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
 
 	if authType != "basic" && authType != "bearer" {
 		return nil, errors.New("invalid auth type: " + authType)
 	}
 
-	if authType == "bearer" {
+	if client == nil {
+		client = NewInsecureClient()
+	}
+
+	b := &BMCAPI{
+		BaseURL:    baseURL,
+		Client:     client,
+		AuthType:   authType,
+		primaryURL: baseURL,
+	}
+	for _, opt := range opts {
+		if err := opt(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.forceHTTPS {
+		b.upgradeToHTTPS()
+	}
+	b.warnUnverifiedLocal()
+
+	b.auth = &bmcApiAuth{AccessToken: b.bearerToken, Username: username, Password: password}
+	if b.lazyAuth || b.bearerToken != "" {
+		return b, nil
+	}
+
+	if err := b.Authenticate(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// NewInsecureClient returns an http.Client that skips TLS certificate verification.
+// The BMC serves HTTPS with a self-signed certificate, so a default client cannot connect to it; skipping
+// verification means the client would also talk to an impersonator, so prefer a client that trusts the
+// BMC's certificate on networks you do not control. The client sets no timeout of its own, as requests
+// are already limited per kind of operation (see WithTimeouts). Its transport is the one from NewTransport.
+func NewInsecureClient() *http.Client {
+	transport := NewTransport()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // Skip TLS verification for self-signed certs
+
+	return &http.Client{Transport: transport}
+}
+
+// NewTransport returns an *http.Transport with connection pooling suited to BMC workloads, for use with
+// WithTransport or in a client of your own. It is http.DefaultTransport, which negotiates HTTP/2 where the
+// BMC offers it, with these changes:
+//   - MaxIdleConnsPerHost is 4 instead of 2, so concurrent calls such as GetUARTAll or Snapshot reuse connections
+//   - MaxIdleConns is 64, enough to keep connections to a fleet of BMCs open when one transport is shared
+//   - IdleConnTimeout is 30 seconds instead of 90, as the BMC's small web server closes idle connections early
+//
+// Certificates are verified as with http.DefaultTransport; NewInsecureClient changes that for self-signed certificates.
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 64
+	transport.MaxIdleConnsPerHost = 4
+	transport.IdleConnTimeout = 30 * time.Second
+
+	return transport
+}
+
+// Authenticate runs the authentication flow again with the stored credentials: for bearer auth a new
+// token is requested, for basic auth a test request is made. NewBMCAPI calls it unless WithLazyAuth is used;
+// call it to re-establish a session, e.g. after the BMC was restarted and forgot its tokens.
+func (b *BMCAPI) Authenticate() error {
+	return b.authenticateContext(context.Background())
+}
+
+// authenticateContext is Authenticate with the requests made under ctx.
+func (b *BMCAPI) authenticateContext(ctx context.Context) error {
+
+	current := b.currentAuth()
+	auth, err := b.authenticate(ctx, current.Username, current.Password)
+	if err != nil {
+		return err
+	}
+	b.setAuth(auth)
+
+	return nil
+}
+
+// lazyAuthenticate requests the first bearer token with WithLazyAuth. Concurrent first calls wait for
+// a single authentication instead of each requesting a token.
+func (b *BMCAPI) lazyAuthenticate(ctx context.Context) error {
 
-		req, err := http.NewRequest("GET", baseURL+"/api/bmc/authenticate", nil)
+	b.authMu.Lock()
+	defer b.authMu.Unlock()
+
+	if b.currentAuth().AccessToken != "" {
+		return nil
+	}
+
+	return b.authenticateContext(ctx)
+
+}
+
+// authenticate runs the authentication flow for the configured auth type with the given credentials.
+// For bearer auth it requests a new token; for basic auth it makes a test request to check the credentials.
+// The credentials are kept in the returned bmcApiAuth so the session can be re-established later.
+func (b *BMCAPI) authenticate(ctx context.Context, username, password string) (*bmcApiAuth, error) {
+
+	authResponse := bmcApiAuth{}
+
+	if b.AuthType == "bearer" {
+
+		authBody, err := json.Marshal(bmcAuthRequest{Username: username, Password: password})
+		if err != nil {
+			return nil, fmt.Errorf("Error encoding authentication request: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", b.endpointURL("/api/bmc/authenticate"), bytes.NewReader(authBody))
 		if err != nil {
 			return nil, fmt.Errorf("Error creating authentication request: %w", err)
 		}
 
 		req.Header.Set("Content-Type", "application/json")
 
-		req.Body = io.NopCloser(strings.NewReader("{\"username\":\"" + username + "\",\"password\":\"" + password + "\"}"))
-
-		resp, err := client.Do(req)
+		resp, err := b.doRequest(req)
 		if err != nil {
 			return nil, fmt.Errorf("Error making request: %w", err)
 		}
@@ -100,22 +346,22 @@ func NewBMCAPI(baseURL, authType, username, password string, client *http.Client
 			return nil, fmt.Errorf("error reading response body: %w", err)
 		}
 
-		if err := json.Unmarshal(bodyBytes, &authResponse); err != nil {
-			return nil, fmt.Errorf("error parsing json in /token response: %+v", err)
+		if err := unmarshalResponse(bodyBytes, &authResponse); err != nil {
+			return nil, fmt.Errorf("error parsing json in /token response: %w", err)
 		}
 		if authResponse.AccessToken == "" {
 			return nil, fmt.Errorf("Authentication response does not contain an auth token")
 		}
 
-	} else if authType == "basic" {
+	} else if b.AuthType == "basic" {
 
-		req, err := http.NewRequest("GET", baseURL+"/api/bmc?opt=get&type=info", nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", b.endpointURL(infoEndpoint), nil)
 		if err != nil {
 			return nil, fmt.Errorf("Error creating authentication request: %w", err)
 		}
 		req.SetBasicAuth(username, password)
 
-		resp, err := client.Do(req)
+		resp, err := b.doRequest(req)
 		if err != nil {
 			return nil, fmt.Errorf("Error making authentication test request: %w", err)
 		}
@@ -125,30 +371,98 @@ func NewBMCAPI(baseURL, authType, username, password string, client *http.Client
 		if resp.StatusCode != http.StatusOK {
 			return nil, fmt.Errorf("Error from authentication test: %s", resp.Status)
 		}
+	}
+
+	// Store the credentials in authResponse
+	authResponse.Username = username
+	authResponse.Password = password
+
+	return &authResponse, nil
+}
+
+// UpdateCredentials replaces the credentials used by b, e.g. after the BMC password was changed.
+// The new credentials are checked against the BMC first: for bearer auth a new token is requested,
+// for basic auth a test request is made. If that fails, the previous credentials stay in use.
+func (b *BMCAPI) UpdateCredentials(username, password string) error {
+
+	auth, err := b.authenticate(context.Background(), username, password)
+	if err != nil {
+		return fmt.Errorf("new credentials were not accepted: %w", err)
+	}
+	b.setAuth(auth)
+
+	return nil
+}
+
+// Validate reports whether the BMC currently accepts the stored credentials or token, by making a small
+// authenticated request. A rejected session (401 Unauthorized or 403 Forbidden) reports false with no error;
+// an error means the question could not be answered, e.g. because the BMC is unreachable.
+// Validate does not authenticate: with WithLazyAuth and bearer auth it reports false until a token was requested.
+func (b *BMCAPI) Validate() (bool, error) {
+
+	if b.AuthType == "bearer" && b.currentAuth().AccessToken == "" {
+		return false, nil
+	}
+
+	_, err := b.bmcAPICallContext(withoutReauth(context.Background()), infoEndpoint)
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error during Validate call: %w", err)
+	}
+
+	return true, nil
+
+}
+
+// normalizeBaseURL checks that baseURL is an absolute http or https URL with a host
+// and strips any trailing slash so endpoints can be appended directly.
+func normalizeBaseURL(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		// The *url.Error would repeat baseURL, which may contain a password, so only keep the cause
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
 
-		// Store basic auth credentials in authResponse
-		authResponse.Username = username
-		authResponse.Password = password
+	// Leave any user info out of error messages
+	display := *u
+	display.User = nil
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid base URL %q: scheme must be http or https (e.g. %s)", display.String(), tpiDefaultURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid base URL %q: missing host", display.String())
 	}
 
-	return &BMCAPI{
-		auth:     &authResponse,
-		BaseURL:  baseURL,
-		Client:   client,
-		AuthType: authType,
-	}, nil
+	return strings.TrimRight(baseURL, "/"), nil
 }
 
+// Other returns the firmware version and build details of the BMC along with its IP and MAC address.
 func (b *BMCAPI) Other() (*bmcOther, error) {
+	other, _, err := b.OtherWithResponse()
+	return other, err
+}
 
-	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=get&type=other")
+// OtherWithResponse is Other that also returns the HTTP response, e.g. to read its headers.
+// The response body has already been read and closed; Body holds a copy of it.
+func (b *BMCAPI) OtherWithResponse() (*bmcOther, *http.Response, error) {
+
+	bodyBytes, resp, err := b.bmcAPICallWithResponse(context.Background(), "/api/bmc?opt=get&type=other")
 	if err != nil {
-		return nil, fmt.Errorf("error during USB Boot API call: %w", err)
+		return nil, resp, fmt.Errorf("error during Other API call: %w", err)
 	}
 
 	result, err := b.objectAPIParse(bodyBytes)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing response: %w", err)
+		return nil, resp, fmt.Errorf("error parsing response: %w", err)
 	}
 
 	bmcOther := bmcOther{
@@ -161,21 +475,66 @@ func (b *BMCAPI) Other() (*bmcOther, error) {
 		Version:      result["version"],
 	}
 
-	return &bmcOther, nil
+	return &bmcOther, resp, nil
+
+}
+
+// buildTimeLayouts are the formats firmware versions report their build time in, tried in order.
+// The usual one is "2025-01-17 17:12:52-00:00"; some builds leave out the offset, which means UTC.
+var buildTimeLayouts = []string{
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05Z07:00",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+}
+
+// BuildTime returns when the BMC firmware was built, parsed from the buildtime Other reports, e.g. to compare
+// the firmware age of several boards. A build time that is missing or in an unknown format is an error.
+func (b *BMCAPI) BuildTime() (time.Time, error) {
+	other, err := b.Other()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseBuildTime(other.Buildtime)
+}
+
+// parseBuildTime is a helper function that parses a firmware build time in one of buildTimeLayouts.
+func parseBuildTime(buildtime string) (time.Time, error) {
+
+	buildtime = strings.TrimSpace(buildtime)
+	if buildtime == "" || buildtime == unknownValue {
+		return time.Time{}, fmt.Errorf("BMC does not report its build time")
+	}
+
+	for _, layout := range buildTimeLayouts {
+		if t, err := time.Parse(layout, buildtime); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized build time %q, want a time like %q", buildtime, "2025-01-17 17:12:52-00:00")
 
 }
 
 // USBBoot sets the USB boot option for the specified node (0-3).
+//
+// Deprecated: Use USBBootResult, which returns a SetResult.
 func (b *BMCAPI) USBBoot(node int) (*string, error) {
+	return resultString(b.USBBootResult(node))
+}
+
+// USBBootResult sets the USB boot option for the specified node (0-3).
+func (b *BMCAPI) USBBootResult(node int) (SetResult, error) {
 
 	// Validate node number
-	if node < 0 || node > 3 {
-		return nil, fmt.Errorf("node number must be between 0 and 3")
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
 	}
 
-	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=usb_boot&node=" + strconv.Itoa(node))
+	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=usb_boot&node=" + Node(node).queryValue())
 	if err != nil {
-		return nil, fmt.Errorf("error during USB Boot API call: %w", err)
+		return SetResult{}, fmt.Errorf("error during USB Boot API call: %w", err)
 	}
 
 	return b.resultAPIParse(bodyBytes)
@@ -183,73 +542,205 @@ func (b *BMCAPI) USBBoot(node int) (*string, error) {
 }
 
 // ClearUSBBoot clears the USB boot option for the specified node (0-3).
+//
+// Deprecated: Use ClearUSBBootResult, which returns a SetResult.
 func (b *BMCAPI) ClearUSBBoot(node int) (*string, error) {
+	return resultString(b.ClearUSBBootResult(node))
+}
+
+// ClearUSBBootResult clears the USB boot option for the specified node (0-3).
+func (b *BMCAPI) ClearUSBBootResult(node int) (SetResult, error) {
 
 	// Validate node number
-	if node < 0 || node > 3 {
-		return nil, fmt.Errorf("node number must be between 0 and 3")
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
 	}
 
-	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=clear_usb_boot&node=" + strconv.Itoa(node))
+	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=clear_usb_boot&node=" + Node(node).queryValue())
 	if err != nil {
-		return nil, fmt.Errorf("error during Clear USB Boot API call: %w", err)
+		return SetResult{}, fmt.Errorf("error during Clear USB Boot API call: %w", err)
 	}
 
 	return b.resultAPIParse(bodyBytes)
 
 }
 
-// ResetNetwork resets the
+// USBBootStatus reports which nodes have the USB boot option set, indexed by node (0-3) like the rest of the SDK.
+// The firmware reports the flags for nodes 1-4, so node 0 is read from the "node1" entry.
+// Firmware that cannot report the option returns ErrUnsupported.
+func (b *BMCAPI) USBBootStatus() ([4]bool, error) {
+
+	var status [4]bool
+
+	bodyBytes, err := b.capabilityAPICall("usb boot status", "/api/bmc?opt=get&type=usb_boot")
+	if err != nil {
+		return status, fmt.Errorf("error during USB Boot Status API call: %w", err)
+	}
+
+	// The flags are keyed by node like the power status, {"response":[{"result":[{"node1":<set>, ...}] }]}
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return status, fmt.Errorf("error parsing usb boot status response: %w", err)
+	}
+
+	for node := range status {
+		key := Node(node).key()
+		raw, ok := result[key]
+		if !ok {
+			return status, fmt.Errorf("usb boot status response has no entry for node %d (%s)", node, key)
+		}
+		if status[node], err = parsePowerValue(raw); err != nil {
+			return status, fmt.Errorf("invalid usb boot status for node %d: %w", node, err)
+		}
+	}
+
+	return status, nil
+
+}
+
+// ResetNetwork resets the board's Ethernet switch, see ResetNetworkResult.
+//
+// Deprecated: Use ResetNetworkResult, which returns a SetResult.
 func (b *BMCAPI) ResetNetwork() (*string, error) {
+	return resultString(b.ResetNetworkResult())
+}
+
+// ResetNetworkResult resets the board's Ethernet switch, which all nodes and the BMC are connected to.
+// Every node briefly loses its network link, not only one. The firmware has no way to reset or reassign
+// the port of a single node; to get one node a new link, reset or power cycle that node instead.
+func (b *BMCAPI) ResetNetworkResult() (SetResult, error) {
 	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=network")
 	if err != nil {
-		return nil, fmt.Errorf("error during Reset Network Switch call: %w", err)
+		return SetResult{}, fmt.Errorf("error during Reset Network Switch call: %w", err)
 	}
 
 	return b.resultAPIParse(bodyBytes)
 }
 
 // NodetoMSD reboots a node into USB Mass Storage Device (MSD) mode.
+//
+// Deprecated: Use NodetoMSDResult, which returns a SetResult.
 func (b *BMCAPI) NodetoMSD(node int) (*string, error) {
+	return resultString(b.NodetoMSDResult(node))
+}
+
+// NodetoMSDResult reboots a node into USB Mass Storage Device (MSD) mode.
+func (b *BMCAPI) NodetoMSDResult(node int) (SetResult, error) {
 	// Validate node number
-	if node < 0 || node > 3 {
-		return nil, fmt.Errorf("node number must be between 0 and 3")
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
 	}
 
-	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=node_to_msd&node=" + strconv.Itoa(node))
+	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=node_to_msd&node=" + Node(node).queryValue())
 	if err != nil {
-		return nil, fmt.Errorf("error during Node to MSD call: %w", err)
+		return SetResult{}, fmt.Errorf("error during Node to MSD call: %w", err)
 	}
 
 	return b.resultAPIParse(bodyBytes)
 
 }
 
+// NodeToNormal takes a node out of USB Mass Storage Device (MSD) mode, undoing NodetoMSD.
+// It clears the node's USB boot flag and resets the node so it boots from its own storage again.
+// The USB routing set up for MSD mode is left as is.
+//
+// Deprecated: Use NodeToNormalResult, which returns a SetResult.
+func (b *BMCAPI) NodeToNormal(node int) (*string, error) {
+	return resultString(b.NodeToNormalResult(node))
+}
+
+// NodeToNormalResult takes a node out of USB Mass Storage Device (MSD) mode, undoing NodetoMSD.
+// It clears the node's USB boot flag and resets the node so it boots from its own storage again.
+// The USB routing set up for MSD mode is left as is.
+func (b *BMCAPI) NodeToNormalResult(node int) (SetResult, error) {
+	if _, err := b.ClearUSBBootResult(node); err != nil {
+		return SetResult{}, err
+	}
+
+	return b.ResetNodeResult(node)
+}
+
+// ResetNode resets the specified node (0-3).
+//
+// Deprecated: Use ResetNodeResult, which returns a SetResult.
+func (b *BMCAPI) ResetNode(node int) (*string, error) {
+	return resultString(b.ResetNodeResult(node))
+}
+
+// ResetNodeResult resets the specified node (0-3); see ResetNodeWithType for a power cycle instead.
+func (b *BMCAPI) ResetNodeResult(node int) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+
+	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=reset&node=" + Node(node).queryValue())
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during Reset Node call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
+
 // SetPower sets power status of specified nodes.
 // The powerState parameter should be 0 for off and 1 for on.
+//
+// Deprecated: Use SetPowerResult, which returns a SetResult.
 func (b *BMCAPI) SetPower(node, powerState int) (*string, error) {
+	return resultString(b.SetPowerResult(node, powerState))
+}
+
+// SetPowerResult sets power status of the specified node (0-3).
+// The powerState parameter should be 0 for off and 1 for on.
+// The firmware keys power by 1-based node, so node 0 is the entry GetPower reports as "node1" (see Node).
+// Powering off a node protected with WithProtectedNodes returns ErrProtectedNode; see ForceSetPower.
+func (b *BMCAPI) SetPowerResult(node, powerState int) (SetResult, error) {
+	return b.setPower(node, powerState, false)
+}
+
+// setPower is a helper function for SetPowerResult and ForceSetPower that refuses to power off
+// a protected node unless force is set.
+func (b *BMCAPI) setPower(node, powerState int, force bool) (SetResult, error) {
 	// Validate node number
-	if node < 0 || node > 3 {
-		return nil, fmt.Errorf("node number must be between 0 and 3")
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
 	}
 	// Validate powerState
 	if powerState < 0 || powerState > 1 {
-		return nil, fmt.Errorf("powerState must be 0 (off) or 1 (on)")
+		return SetResult{}, ErrInvalidPowerState
+	}
+	if powerState == 0 && !force && b.protectedNodes[node] {
+		return SetResult{}, fmt.Errorf("powering off node %d: %w", node, ErrProtectedNode)
 	}
 
-	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=power&type=set&node" + strconv.Itoa(node) + "=" + strconv.Itoa(powerState))
+	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=power&" + Node(node).key() + "=" + strconv.Itoa(powerState))
 	if err != nil {
-		return nil, fmt.Errorf("error during Set Power call: %w", err)
+		return SetResult{}, fmt.Errorf("error during Set Power call: %w", err)
 	}
 
 	return b.resultAPIParse(bodyBytes)
 }
 
 // GetPower Gets power status of all nodes.
+// States are normalized to "1" (on) and "0" (off) whichever representation the firmware uses.
+// The map is keyed as the firmware reports it, "node1" to "node4" for nodes 0-3; use PowerStates for
+// states indexed by node like the rest of the SDK.
 func (b *BMCAPI) GetPower() (map[string]string, error) {
-	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=get&type=power")
+	power, _, err := b.GetPowerWithResponse()
+	return power, err
+}
+
+// GetPowerWithResponse is GetPower that also returns the HTTP response, e.g. to read its headers.
+// The response body has already been read and closed; Body holds a copy of it.
+func (b *BMCAPI) GetPowerWithResponse() (map[string]string, *http.Response, error) {
+	return b.getPowerWithResponse(context.Background())
+}
+
+// getPowerWithResponse is GetPowerWithResponse with the request made under ctx.
+func (b *BMCAPI) getPowerWithResponse(ctx context.Context) (map[string]string, *http.Response, error) {
+	bodyBytes, resp, err := b.bmcAPICallWithResponse(ctx, "/api/bmc?opt=get&type=power")
 	if err != nil {
-		return nil, fmt.Errorf("error during Get Power call: %w", err)
+		return nil, resp, fmt.Errorf("error during Get Power call: %w", err)
 	}
 
 	// We want to return the whole map here, as it's a map of node numbers to power states
@@ -259,77 +750,433 @@ func (b *BMCAPI) GetPower() (map[string]string, error) {
 	// 	"node3": "1",
 	// 	"node4": "0",
 	// }
-	return b.objectAPIParse(bodyBytes)
+	power, err := powerAPIParse(bodyBytes)
+	return power, resp, err
+
+}
 
+// baseURL returns the base URL requests are currently sent to.
+func (b *BMCAPI) baseURL() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.BaseURL
+}
+
+// endpointURL returns the full URL of endpoint, a path starting with "/api/bmc", with the API prefix applied.
+func (b *BMCAPI) endpointURL(endpoint string) string {
+	if b.customAPIPrefix {
+		if rest, ok := strings.CutPrefix(endpoint, defaultAPIPrefix); ok {
+			endpoint = b.apiPrefix + rest
+		}
+	}
+	return b.baseURL() + endpoint
 }
 
 // bmcAPICall is a helper function that makes a GET request to the BMC API and returns the response body as a byte slice.
 func (b *BMCAPI) bmcAPICall(endpoint string) ([]byte, error) {
+	return b.bmcAPICallContext(context.Background(), endpoint)
+}
+
+// bmcAPICallContext is bmcAPICall with the request made under ctx, so cancellation and tracing spans carry over to it.
+func (b *BMCAPI) bmcAPICallContext(ctx context.Context, endpoint string) ([]byte, error) {
+	bodyBytes, _, err := b.bmcAPICallWithResponse(ctx, endpoint)
+	return bodyBytes, err
+}
+
+// bmcAPICallWithResponse is a helper function like bmcAPICall that also returns the HTTP response.
+// The response body is read and closed here and replaced with an in-memory copy, so callers never need to close it.
+// The response is also returned with an HTTPError, so headers of error responses can be inspected.
+func (b *BMCAPI) bmcAPICallWithResponse(ctx context.Context, endpoint string) ([]byte, *http.Response, error) {
+	return b.bmcAPIRequest(ctx, "GET", endpoint, "", nil)
+}
 
-	// Create a new http request to the get other endpoint
-	req, err := http.NewRequest("GET", b.BaseURL+endpoint, nil)
+// bmcAPIRequest is a helper function like bmcAPICallWithResponse for a request with the given method and body.
+// The body is sent with contentType; without a body, bearer auth requests are sent as application/json.
+func (b *BMCAPI) bmcAPIRequest(ctx context.Context, method, endpoint, contentType string, body []byte) ([]byte, *http.Response, error) {
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	// Create a new http request to the endpoint
+	req, err := http.NewRequestWithContext(ctx, method, b.endpointURL(endpoint), bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("Error creating request: %w", err)
+		return nil, nil, fmt.Errorf("Error creating request: %w", err)
 	}
 
-	// Set the authorization headers
-	if b.AuthType == "basic" {
-		req.SetBasicAuth(b.auth.Username, b.auth.Password)
+	if b.dryRun != nil && isWriteRequest(req) {
+		return b.dryRunResponse(req)
+	}
+
+	// Cached responses are answered without a request, and writes may change what was cached (see WithCache)
+	if b.cache != nil && isWriteRequest(req) {
+		b.cache.invalidate()
+	} else if b.cache != nil && cacheable(req) {
+		if bodyBytes, resp, ok := b.cache.get(endpoint, req); ok {
+			return bodyBytes, resp, nil
+		}
+	}
+
+	// Identical reads made at the same time share one request to the BMC, see flightGroup. Reads that must not
+	// re-authenticate, such as those of Validate, only share with each other, as the outcome of a rejected session differs.
+	if _, hasDeadline := ctx.Deadline(); method == "GET" && !isWriteRequest(req) && !hasDeadline {
+		key := endpoint
+		if ctx.Value(noReauthKey{}) != nil {
+			key = "noreauth " + endpoint
+		}
+		return b.flights.do(ctx, key, func(ctx context.Context) ([]byte, *http.Response, error) {
+			return b.sendAPIRequest(ctx, req.WithContext(ctx), endpoint, contentType)
+		})
+	}
+
+	return b.sendAPIRequest(ctx, req, endpoint, contentType)
+
+}
+
+// sendAPIRequest is a helper function for bmcAPIRequest that authenticates and sends req, re-establishing a
+// rejected bearer session once, and caches the response if endpoint is cached.
+func (b *BMCAPI) sendAPIRequest(ctx context.Context, req *http.Request, endpoint, contentType string) ([]byte, *http.Response, error) {
+
+	// With lazy auth no bearer token has been requested yet before the first call
+	if b.AuthType == "bearer" && b.currentAuth().AccessToken == "" {
+		if err := b.lazyAuthenticate(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if isWriteRequest(req) {
+		b.setIdempotencyKey(req)
+	}
+
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+
+	token := b.currentAuth().AccessToken
+	b.setAuthHeaders(req)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	} else if b.AuthType == "bearer" {
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+b.auth.AccessToken)
 	}
 
-	resp, err := b.Client.Do(req)
+	bodyBytes, resp, err := b.readAPIResponse(req)
+
+	// A rejected bearer session is re-established once, with backoff between failed attempts (see WithReauthBackoff)
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized && b.canReauthenticate(ctx) {
+		if err := b.reauthenticate(ctx, token); err != nil {
+			return nil, resp, err
+		}
+		retry := req.Clone(ctx)
+		if req.GetBody != nil {
+			retry.Body, _ = req.GetBody()
+		}
+		b.setAuthHeaders(retry)
+		bodyBytes, resp, err = b.readAPIResponse(retry)
+	}
+
+	if err == nil && b.cache != nil && cacheable(req) {
+		b.cache.put(endpoint, bodyBytes, resp)
+	}
+
+	return bodyBytes, resp, err
+
+}
+
+// readAPIResponse is a helper function for bmcAPICallWithResponse that sends req and reads the response body,
+// decompressing it if the BMC compressed it.
+func (b *BMCAPI) readAPIResponse(req *http.Request) ([]byte, *http.Response, error) {
+
+	resp, err := b.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("Error making request: %w", err)
+		return nil, nil, fmt.Errorf("Error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	bodyBytes, err := readBody(resp)
+	resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	if resp.Header.Get("Content-Encoding") != "" {
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = int64(len(bodyBytes))
+		resp.Uncompressed = true
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http error in response: %s", resp.Status)
+		return nil, resp, httpError(resp, bodyBytes)
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, resp, fmt.Errorf("error reading response body: %w: %w", ErrTruncatedResponse, err)
+	}
+	if err != nil {
+		return nil, resp, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	b.lastContact.Store(time.Now().UnixNano())
+
+	return bodyBytes, resp, nil
+
+}
+
+// LastContact returns when an API call last succeeded, i.e. the BMC answered it with 200 OK,
+// e.g. to alert when a BMC has been silent for too long. It is the zero time before the first success.
+func (b *BMCAPI) LastContact() time.Time {
+	nanos := b.lastContact.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// setAuthHeaders is a helper function that sets the authorization headers for the configured auth type on req.
+func (b *BMCAPI) setAuthHeaders(req *http.Request) {
+	auth := b.currentAuth()
+	if b.AuthType == "basic" {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	} else if b.AuthType == "bearer" {
+		req.Header.Set("Authorization", "Bearer "+auth.AccessToken)
+	}
+}
+
+// currentAuth returns the credentials and token requests are currently made with.
+func (b *BMCAPI) currentAuth() *bmcApiAuth {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.auth
+}
+
+// setAuth replaces the credentials and token requests are made with.
+func (b *BMCAPI) setAuth(auth *bmcApiAuth) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.auth = auth
+}
+
+// doRequest is a helper function that sends every request made to the BMC, including authentication requests.
+// Headers set with WithDefaultHeaders are added first unless req already has them (see WithDefaultHeaders).
+// Request interceptors run next, in the order they were added (see WithRequestInterceptor), and response
+// interceptors run last with the outcome, whether it is a response or an error (see WithResponseInterceptor).
+// If the BMC cannot be reached and fallback URLs are configured, the request is retried against them (see WithFallbackURLs).
+func (b *BMCAPI) doRequest(req *http.Request) (*http.Response, error) {
+
+	// Default headers go under the headers set for the request, so they never replace its auth headers
+	for name, values := range b.defaultHeaders {
+		if _, ok := req.Header[name]; !ok && name != "Authorization" {
+			req.Header[name] = slices.Clone(values)
+		}
+	}
+
+	start := time.Now()
+	resp, err := b.interceptAndSend(req)
+	for _, observe := range b.responseInterceptors {
+		observe(resp, time.Since(start), err)
+	}
+
+	return resp, err
+
+}
+
+// interceptAndSend is a helper function for doRequest that runs the request interceptors and sends req,
+// failing over to the fallback URLs if needed.
+func (b *BMCAPI) interceptAndSend(req *http.Request) (*http.Response, error) {
+
+	for _, intercept := range b.requestInterceptors {
+		if err := intercept(req); err != nil {
+			return nil, fmt.Errorf("request interceptor: %w", err)
+		}
+	}
+
+	resp, err := b.logRequest(req)
+	if err == nil || len(b.fallbackURLs) == 0 || !isConnectionError(err) {
+		return resp, err
+	}
+
+	return b.failover(req, err)
+
+}
+
+// logRequest is a helper function that sends req, logging it at debug level when a logger is configured.
+func (b *BMCAPI) logRequest(req *http.Request) (*http.Response, error) {
+
+	if b.logger == nil || !b.logger.Enabled(req.Context(), slog.LevelDebug) {
+		return b.send(req)
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	start := time.Now()
+	resp, err := b.send(req)
 
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Duration("duration", time.Since(start)),
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	b.logger.LogAttrs(req.Context(), slog.LevelDebug, "bmc request", attrs...)
+
+	return resp, err
+
+}
+
+// send is a helper function that hands req to the HTTP client, limited by the timeout for its kind of operation (see WithTimeouts).
+// Redirects are followed with the request's body and headers intact (see doFollowingRedirects).
+// Transport errors repeat the request URL, so it is redacted to keep UART commands and similar out of returned errors.
+// Certificate errors get a hint about the BMC's self-signed certificate.
+func (b *BMCAPI) send(req *http.Request) (*http.Response, error) {
+
+	req, cancel := b.withRequestTimeout(req)
+	resp, err := b.doFollowingRedirects(req)
+	if err != nil {
+		cancel()
+	} else {
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactURL(req.URL)
+	}
+
+	return resp, explainCertificateError(err)
+
+}
+
+// capabilityAPICall is a helper function like bmcAPICall for endpoints that only exist on some firmware versions.
+// Firmware that does not know the requested type rejects it with 400 Bad Request or 404 Not Found, which is reported as ErrUnsupported.
+func (b *BMCAPI) capabilityAPICall(feature, endpoint string) ([]byte, error) {
+	return b.capabilityAPICallContext(context.Background(), feature, endpoint)
+}
+
+// capabilityAPICallContext is capabilityAPICall with the request made under ctx.
+func (b *BMCAPI) capabilityAPICallContext(ctx context.Context, feature, endpoint string) ([]byte, error) {
+
+	bodyBytes, err := b.bmcAPICallContext(ctx, endpoint)
+
+	return bodyBytes, capabilityError(feature, err)
+
+}
+
+// capabilityError is a helper function for capability requests that reports err as ErrUnsupported if the
+// firmware rejected the request with 400 Bad Request, 404 Not Found or, for its method, 405 Method Not Allowed.
+func capabilityError(feature string, err error) error {
+
+	// The firmware may reject a type it knows with 400 Bad Request while it is busy
+	var httpErr *HTTPError
+	if !errors.Is(err, ErrBusy) && errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusBadRequest || httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusMethodNotAllowed) {
+		return fmt.Errorf("%s: %w", feature, ErrUnsupported)
+	}
+
+	return err
+
+}
+
+// unmarshalResponse is a helper function that decodes a JSON response body into v.
+// Bodies that start with "<" are HTML (usually the login page) and are reported as ErrNonJSONResponse.
+// Bodies that end before their JSON does are reported as ErrTruncatedResponse.
+func unmarshalResponse(bodyBytes []byte, v any) error {
+
+	trimmed := bytes.TrimSpace(bodyBytes)
+	if len(trimmed) > 0 && trimmed[0] == '<' {
+		return ErrNonJSONResponse
+	}
+
+	err := json.Unmarshal(bodyBytes, v)
+	if err != nil && len(trimmed) > 0 {
+		// A decoder reports input that ends inside a JSON value as io.ErrUnexpectedEOF, unlike json.Unmarshal
+		var raw json.RawMessage
+		if errors.Is(json.NewDecoder(bytes.NewReader(trimmed)).Decode(&raw), io.ErrUnexpectedEOF) {
+			return fmt.Errorf("%w (%d bytes received)", ErrTruncatedResponse, len(bodyBytes))
+		}
+	}
+
+	return err
+
+}
+
+// maxBodySnippet is the most bytes of a response body bodySnippet includes in an error.
+const maxBodySnippet = 200
+
+// sensitiveBodyFields matches the JSON string values of fields that may hold credentials, such as the token the
+// authenticate endpoint returns as "id", so bodySnippet can redact them.
+var sensitiveBodyFields = regexp.MustCompile(`("(?:password|token|id)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// bodySnippet returns the start of a response body for an error message, quoted, with credentials redacted
+// and cut off after maxBodySnippet bytes.
+func bodySnippet(bodyBytes []byte) string {
+
+	redacted := sensitiveBodyFields.ReplaceAll(bytes.TrimSpace(bodyBytes), []byte(`$1"REDACTED"`))
+	if len(redacted) > maxBodySnippet {
+		return strconv.Quote(string(redacted[:maxBodySnippet])) + "..."
 	}
 
-	return bodyBytes, nil
+	return strconv.Quote(string(redacted))
 
 }
 
-// resultAPIParse is a helper function that parses the response from the BMC API and returns the result as a map of strings.
+// resultAPIParse is a helper function that parses the response from the BMC API and returns the result as a SetResult.
 // It expects the response to be in the format {"response":[{"result":"<result>" }]}
-func (b *BMCAPI) resultAPIParse(bodyBytes []byte) (*string, error) {
+// Any result other than "ok" is returned as an *APIError.
+func (b *BMCAPI) resultAPIParse(bodyBytes []byte) (SetResult, error) {
 
 	var parsed bmcResultAPIResponse
 
-	if err := json.Unmarshal(bodyBytes, &parsed); err != nil {
-		return nil, fmt.Errorf("error parsing json in token response: %+v", err)
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return SetResult{}, fmt.Errorf("error parsing json in API response: %w (body: %s)", err, bodySnippet(bodyBytes))
+	}
+
+	if len(parsed.Response) == 0 {
+		return SetResult{}, fmt.Errorf("no data in response (body: %s)", bodySnippet(bodyBytes))
 	}
 
 	result := parsed.Response[0].Result
 	if result == "" {
-		return nil, fmt.Errorf("result field in API response is empty")
+		return SetResult{}, fmt.Errorf("result field in API response is empty")
+	}
+	if result != "ok" {
+		return SetResult{}, &APIError{Result: result}
 	}
 
-	return &result, nil
+	return SetResult{Raw: result}, nil
 
 }
 
 // objectAPIParse is a helper function that parses the response from the BMC API and returns the result as a map of strings.
 // It expects the response to be in the format {"response":[{"result":[{<resultobject>}] }]}
+// Numbers and booleans are converted to their JSON text, e.g. "42" or "true", and null to "".
 func (b *BMCAPI) objectAPIParse(bodyBytes []byte) (map[string]string, error) {
 
+	raw, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(raw))
+	for key, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			text = string(value)
+		}
+		result[key] = text
+	}
+
+	return result, nil
+
+}
+
+// objectAPIParseRaw is a helper function like objectAPIParse that keeps each value as raw JSON,
+// so numbers and booleans can be decoded with their types.
+func (b *BMCAPI) objectAPIParseRaw(bodyBytes []byte) (map[string]json.RawMessage, error) {
+
 	var parsed bmcObjectAPIResponse
 
-	if err := json.Unmarshal(bodyBytes, &parsed); err != nil {
-		return nil, fmt.Errorf("error parsing json in token response: %+v", err)
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing json in API response: %w (body: %s)", err, bodySnippet(bodyBytes))
 	}
 	if len(parsed.Response) == 0 || len(parsed.Response[0].Result) == 0 {
-		return nil, fmt.Errorf("no data in response")
+		return nil, fmt.Errorf("no data in response (body: %s)", bodySnippet(bodyBytes))
 	}
 
 	return parsed.Response[0].Result[0], nil
@@ -2,11 +2,17 @@ package bmcapi
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 // mockOther implements http.RoundTripper for testing
@@ -73,3 +79,671 @@ func TestBMCAPI_Other(t *testing.T) {
 		}
 	})
 }
+
+// mockTransport implements http.RoundTripper by handing every request to the wrapped function.
+type mockTransport func(req *http.Request) (*http.Response, error)
+
+func (m mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return m(req)
+}
+
+// mockResponse builds a canned http.Response with the given status code and body.
+func mockResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     strconv.Itoa(status) + " " + http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}
+}
+
+// newMockBMCAPI returns a BMCAPI using basic auth that sends its requests to the given transport.
+func newMockBMCAPI(transport http.RoundTripper) *BMCAPI {
+	return &BMCAPI{
+		auth:       &bmcApiAuth{Username: "user", Password: "pass"},
+		BaseURL:    "http://mock",
+		Client:     &http.Client{Transport: transport},
+		AuthType:   "basic",
+		primaryURL: "http://mock",
+	}
+}
+
+func TestBMCOther_Addresses(t *testing.T) {
+	other := &bmcOther{IP: "192.168.1.42", MAC: "12:34:56:78:9a:bc"}
+	ip, ok, err := other.IPAddress()
+	if err != nil || !ok || !ip.Equal(net.IPv4(192, 168, 1, 42)) {
+		t.Errorf("IPAddress() = %v, %v, %v", ip, ok, err)
+	}
+	mac, ok, err := other.HardwareAddr()
+	if err != nil || !ok || mac.String() != "12:34:56:78:9a:bc" {
+		t.Errorf("HardwareAddr() = %v, %v, %v", mac, ok, err)
+	}
+
+	unknown := &bmcOther{IP: "Unknown", MAC: "Unknown"}
+	if ip, ok, err := unknown.IPAddress(); ip != nil || ok || err != nil {
+		t.Errorf("IPAddress() for Unknown = %v, %v, %v, want not ok and no error", ip, ok, err)
+	}
+	if mac, ok, err := unknown.HardwareAddr(); mac != nil || ok || err != nil {
+		t.Errorf("HardwareAddr() for Unknown = %v, %v, %v, want not ok and no error", mac, ok, err)
+	}
+
+	invalid := &bmcOther{IP: "not an ip", MAC: "zz"}
+	if _, _, err := invalid.IPAddress(); err == nil {
+		t.Errorf("expected error for an invalid IP")
+	}
+	if _, _, err := invalid.HardwareAddr(); err == nil {
+		t.Errorf("expected error for an invalid MAC")
+	}
+}
+
+func TestNewBMCAPI_BaseURL(t *testing.T) {
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{}`), nil
+	})}
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", baseURL: "", want: "https://turingpi.local"},
+		{name: "valid https", baseURL: "https://192.168.1.10", want: "https://192.168.1.10"},
+		{name: "valid http with port", baseURL: "http://turingpi.local:8080", want: "http://turingpi.local:8080"},
+		{name: "trailing slash", baseURL: "https://turingpi.local/", want: "https://turingpi.local"},
+		{name: "missing scheme", baseURL: "turingpi.local", wantErr: true},
+		{name: "unsupported scheme", baseURL: "ftp://turingpi.local", wantErr: true},
+		{name: "missing host", baseURL: "https://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bmc, err := NewBMCAPI(tt.baseURL, "basic", "user", "pass", client)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.baseURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bmc.BaseURL != tt.want {
+				t.Errorf("BaseURL = %q, want %q", bmc.BaseURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBMCAPI_ErrorsRedactCredentials(t *testing.T) {
+	const username, password = "admin-user", "s3cret-pass"
+
+	tests := []struct {
+		name      string
+		baseURL   string
+		authType  string
+		transport mockTransport
+	}{
+		{
+			name:     "bearer rejected",
+			baseURL:  "http://mock",
+			authType: "bearer",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return mockResponse(http.StatusUnauthorized, `{"error":"bad credentials"}`), nil
+			},
+		},
+		{
+			name:     "bearer malformed response",
+			baseURL:  "http://mock",
+			authType: "bearer",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return mockResponse(http.StatusOK, `{"id":`), nil
+			},
+		},
+		{
+			name:     "basic rejected",
+			baseURL:  "http://mock",
+			authType: "basic",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return mockResponse(http.StatusUnauthorized, ""), nil
+			},
+		},
+		{
+			name:     "basic transport error",
+			baseURL:  "http://mock",
+			authType: "basic",
+			transport: func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+		},
+		{
+			name:     "credentials in base URL",
+			baseURL:  "ftp://" + username + ":" + password + "@mock",
+			authType: "basic",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{Transport: tt.transport}
+			_, err := NewBMCAPI(tt.baseURL, tt.authType, username, password, client)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			for _, secret := range []string{username, password} {
+				if strings.Contains(err.Error(), secret) {
+					t.Errorf("error %q contains credential %q", err, secret)
+				}
+			}
+		})
+	}
+}
+
+func TestBMCAPI_TransportErrorRedactsQuery(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection reset")
+	}))
+
+	_, err := bmc.RawGet("/api/bmc?opt=set&type=uart&node=0&cmd=hunter2")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("error %q contains the UART command", err)
+	}
+}
+
+func TestNewBMCAPI_BearerSpecialCharacters(t *testing.T) {
+	const username, password = `ad"min`, `pa\ss"wo\"rd`
+
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		var body bmcAuthRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("authentication body is not valid json: %v", err)
+			return mockResponse(http.StatusBadRequest, ""), nil
+		}
+		if body.Username != username || body.Password != password {
+			t.Errorf("authentication body = %+v, want username %q and password %q", body, username, password)
+		}
+		return mockResponse(http.StatusOK, `{"id":"token-123","name":"cli","description":"test"}`), nil
+	})}
+
+	bmc, err := NewBMCAPI("http://mock", "bearer", username, password, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bmc.auth.AccessToken != "token-123" {
+		t.Errorf("access token = %q, want %q", bmc.auth.AccessToken, "token-123")
+	}
+}
+
+func TestBMCAPI_UpdateCredentials(t *testing.T) {
+	accepted := "pass"
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if _, password, _ := req.BasicAuth(); password != accepted {
+			return mockResponse(http.StatusUnauthorized, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	accepted = "new-pass"
+	if err := bmc.UpdateCredentials("user", "wrong-pass"); err == nil {
+		t.Fatalf("expected rejected credentials to return an error")
+	}
+	if bmc.auth.Password != "pass" {
+		t.Errorf("rejected credentials replaced the stored ones")
+	}
+
+	if err := bmc.UpdateCredentials("user", "new-pass"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.RawGet("/api/bmc?opt=get&type=other"); err != nil {
+		t.Errorf("request with updated credentials failed: %v", err)
+	}
+}
+
+func TestNewBMCAPI_NilClient(t *testing.T) {
+	bmc, err := NewBMCAPI("https://turingpi.local", "basic", "user", "pass", nil, WithLazyAuth())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bmc.Client == nil {
+		t.Fatalf("Client is nil")
+	}
+	transport, ok := bmc.Client.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("default client verifies TLS certificates")
+	}
+	// A client timeout would cut flashes short; requests are limited by WithTimeouts instead
+	if bmc.Client.Timeout != 0 {
+		t.Errorf("default client has a timeout of %v", bmc.Client.Timeout)
+	}
+}
+
+func TestNewBMCAPI_LazyAuth(t *testing.T) {
+	var authRequests int
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			authRequests++
+			return mockResponse(http.StatusOK, `{"id":"token-`+strconv.Itoa(authRequests)+`"}`), nil
+		}
+		if req.Header.Get("Authorization") != "Bearer token-"+strconv.Itoa(authRequests) {
+			t.Errorf("request sent with Authorization %q", req.Header.Get("Authorization"))
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+
+	bmc, err := NewBMCAPI("http://mock", "bearer", "user", "pass", client, WithLazyAuth())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authRequests != 0 {
+		t.Fatalf("NewBMCAPI authenticated despite WithLazyAuth")
+	}
+
+	if _, err := bmc.RawGet("/api/bmc?opt=get&type=other"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.RawGet("/api/bmc?opt=get&type=other"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authRequests != 1 {
+		t.Errorf("authenticated %d times, want once on the first call", authRequests)
+	}
+
+	if err := bmc.Authenticate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bmc.auth.AccessToken != "token-2" {
+		t.Errorf("access token after Authenticate = %q, want %q", bmc.auth.AccessToken, "token-2")
+	}
+}
+
+func TestBMCAPI_SetResult(t *testing.T) {
+	result := "ok"
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":"`+result+`"}]}`), nil
+	}))
+
+	got, err := bmc.USBBootResult(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Ok() || got.Raw != "ok" {
+		t.Errorf("USBBootResult() = %+v, want an ok result", got)
+	}
+
+	// The deprecated methods return the same result as a *string
+	raw, err := bmc.SetPower(2, 1)
+	if err != nil || *raw != "ok" {
+		t.Errorf("SetPower() = %v, %v", raw, err)
+	}
+	if raw, err := bmc.SetPower(4, 1); raw != nil || !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("SetPower(4) = %v, %v, want nil and ErrInvalidNode", raw, err)
+	}
+}
+
+func TestBMCAPI_APIError(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":"node is busy flashing"}]}`), nil
+	}))
+
+	result, err := bmc.SetPowerResult(2, 1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Result != "node is busy flashing" {
+		t.Fatalf("SetPowerResult() error = %v, want an APIError with the firmware's message", err)
+	}
+	if result.Ok() {
+		t.Errorf("SetPowerResult() = %+v with an error, want a zero result", result)
+	}
+
+	if raw, err := bmc.USBBoot(1); raw != nil || !errors.As(err, &apiErr) {
+		t.Errorf("USBBoot() = %v, %v, want nil and an APIError", raw, err)
+	}
+}
+
+func TestBMCAPI_ResetNetworkResult(t *testing.T) {
+	body := `{"response":[{"result":"ok"}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("opt") != "set" || req.URL.Query().Get("type") != "network" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	result, err := bmc.ResetNetworkResult()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Ok() {
+		t.Errorf("ResetNetworkResult() = %+v, want ok", result)
+	}
+
+	// Responses without a result must fail instead of panicking
+	for _, body = range []string{`{"response":[]}`, `{}`, `{"response":[{"result":""}]}`} {
+		if _, err := bmc.ResetNetworkResult(); err == nil {
+			t.Errorf("expected error for response %s", body)
+		}
+	}
+}
+
+func TestBMCAPI_Validate(t *testing.T) {
+	status := http.StatusOK
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(status, `{"response":[{"result":[{}]}]}`), nil
+	}))
+
+	if valid, err := bmc.Validate(); !valid || err != nil {
+		t.Errorf("Validate() = %v, %v, want true", valid, err)
+	}
+
+	status = http.StatusUnauthorized
+	if valid, err := bmc.Validate(); valid || err != nil {
+		t.Errorf("Validate() after 401 = %v, %v, want false and no error", valid, err)
+	}
+
+	status = http.StatusInternalServerError
+	if _, err := bmc.Validate(); err == nil {
+		t.Errorf("expected error for a 500 response")
+	}
+}
+
+func TestBMCAPI_ObjectAPIParseTypes(t *testing.T) {
+	bmc := newMockBMCAPI(nil)
+	body := []byte(`{"response":[{"result":[{"name":"bmc","temp":41.5,"bytes":1073741824,"ok":true,"missing":null}]}]}`)
+
+	raw, err := bmc.objectAPIParseRaw(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var temp float64
+	if err := json.Unmarshal(raw["temp"], &temp); err != nil || temp != 41.5 {
+		t.Errorf("temp = %v, %v, want 41.5", temp, err)
+	}
+
+	got, err := bmc.objectAPIParse(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"name": "bmc", "temp": "41.5", "bytes": "1073741824", "ok": "true", "missing": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("objectAPIParse() = %v, want %v", got, want)
+	}
+}
+
+func TestBMCAPI_ParseErrorBody(t *testing.T) {
+	bmc := newMockBMCAPI(nil)
+
+	_, err := bmc.objectAPIParseRaw([]byte(`{"response":[{"result":{"id":"secret-token","version":"2.0.5"}}]}`))
+	if err == nil || !strings.Contains(err.Error(), `\"version\":\"2.0.5\"`) || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("objectAPIParseRaw() error = %v, want the body with the token redacted", err)
+	}
+
+	_, err = bmc.resultAPIParse([]byte(`{"response":[]}`))
+	if err == nil || !strings.Contains(err.Error(), `{\"response\":[]}`) {
+		t.Errorf("resultAPIParse() error = %v, want the body", err)
+	}
+
+	_, err = bmc.resultAPIParse([]byte(`{"response":"` + strings.Repeat("x", 10000) + `"}`))
+	if err == nil || len(err.Error()) > 2*maxBodySnippet+100 || !strings.HasSuffix(err.Error(), "...)") {
+		t.Errorf("resultAPIParse() error for a large body has length %d, want a truncated snippet", len(err.Error()))
+	}
+}
+
+func TestBMCAPI_HTMLResponse(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		resp := mockResponse(http.StatusOK, "\n<!DOCTYPE html><html><body><form id=\"login\"></form></body></html>")
+		resp.Header.Set("Content-Type", "text/html")
+		return resp, nil
+	}))
+
+	if _, err := bmc.Other(); !errors.Is(err, ErrNonJSONResponse) {
+		t.Errorf("Other() error = %v, want ErrNonJSONResponse", err)
+	}
+	if _, err := bmc.USBBoot(0); !errors.Is(err, ErrNonJSONResponse) {
+		t.Errorf("USBBoot() error = %v, want ErrNonJSONResponse", err)
+	}
+	if _, err := bmc.GetUART(0); !errors.Is(err, ErrNonJSONResponse) {
+		t.Errorf("GetUART() error = %v, want ErrNonJSONResponse", err)
+	}
+}
+
+func TestBMCAPI_TruncatedResponse(t *testing.T) {
+	body := `{"response":[{"result":[{"api":"1.1","build_version":"2024.0`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	if _, err := bmc.Other(); !errors.Is(err, ErrTruncatedResponse) {
+		t.Errorf("Other() error = %v, want ErrTruncatedResponse", err)
+	}
+	if _, err := bmc.GetUART(0); !errors.Is(err, ErrTruncatedResponse) {
+		t.Errorf("GetUART() error = %v, want ErrTruncatedResponse", err)
+	}
+
+	// Malformed but complete JSON is not a truncated response
+	body = `{"response":[{"result":"ok",}]}`
+	if _, err := bmc.USBBootResult(0); err == nil || errors.Is(err, ErrTruncatedResponse) {
+		t.Errorf("USBBootResult() error = %v, want a parse error", err)
+	}
+
+	// The connection dropping before Content-Length bytes were read
+	dropped := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		resp := mockResponse(http.StatusOK, "")
+		resp.Body = io.NopCloser(io.MultiReader(strings.NewReader(`{"response":[`), iotest.ErrReader(io.ErrUnexpectedEOF)))
+		return resp, nil
+	}))
+	if _, err := dropped.Other(); !errors.Is(err, ErrTruncatedResponse) {
+		t.Errorf("Other() error after a dropped connection = %v, want ErrTruncatedResponse", err)
+	}
+}
+
+func TestBMCAPI_OtherWithResponse(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		resp, err := (&mockOther{}).RoundTrip(req)
+		resp.Header.Set("ETag", `"abc"`)
+		return resp, err
+	}))
+
+	other, resp, err := bmc.OtherWithResponse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if other.Version != "2.3.4" {
+		t.Errorf("Version = %q, want %q", other.Version, "2.3.4")
+	}
+	if resp.Header.Get("ETag") != `"abc"` {
+		t.Errorf("ETag header = %q, want %q", resp.Header.Get("ETag"), `"abc"`)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || !strings.Contains(string(body), `"version":"2.3.4"`) {
+		t.Errorf("response body copy = %q, %v", body, err)
+	}
+}
+
+func TestBMCAPI_NodeToNormal(t *testing.T) {
+	var got []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.URL.RawQuery)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	result, err := bmc.NodeToNormal(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != "ok" {
+		t.Errorf("NodeToNormal() = %q, want %q", *result, "ok")
+	}
+	want := []string{"opt=set&type=clear_usb_boot&node=2", "opt=set&type=reset&node=2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+
+	if _, err := bmc.NodeToNormal(4); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("NodeToNormal(4) error = %v, want ErrInvalidNode", err)
+	}
+}
+
+func TestBMCAPI_LastContact(t *testing.T) {
+	status := http.StatusOK
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(status, mockPowerResponse), nil
+	}))
+
+	if !bmc.LastContact().IsZero() {
+		t.Errorf("LastContact() = %v before any call, want the zero time", bmc.LastContact())
+	}
+
+	before := time.Now()
+	if _, err := bmc.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	contact := bmc.LastContact()
+	if contact.Before(before) || contact.After(time.Now()) {
+		t.Errorf("LastContact() = %v, want the time of the call", contact)
+	}
+
+	// Failed calls do not count as contact
+	status = http.StatusInternalServerError
+	bmc.GetPower()
+	if !bmc.LastContact().Equal(contact) {
+		t.Errorf("LastContact() = %v after a failed call, want %v", bmc.LastContact(), contact)
+	}
+}
+
+func TestBMCAPI_ConcurrentUse(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		token  = 0
+		issued = 0
+		calls  = 0
+	)
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if req.URL.Path == "/api/bmc/authenticate" {
+			issued++
+			token++
+			return mockResponse(http.StatusOK, `{"id":"token`+strconv.Itoa(token)+`"}`), nil
+		}
+		if req.Header.Get("Authorization") != "Bearer token"+strconv.Itoa(token) {
+			return mockResponse(http.StatusUnauthorized, ""), nil
+		}
+
+		// The BMC forgets its tokens every 25 calls, as if it restarted
+		calls++
+		if calls%25 == 0 {
+			token++
+		}
+		if req.URL.Query().Get("opt") == "set" {
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		}
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+	bmc.auth.AccessToken = ""
+	if err := WithReauthBackoff(time.Millisecond, time.Millisecond, 5)(bmc); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for worker := 0; worker < 16; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				var err error
+				switch (worker + i) % 4 {
+				case 0:
+					_, err = bmc.GetPower()
+				case 1:
+					_, err = bmc.SetPowerResult(worker%4, i%2)
+				case 2:
+					_, err = bmc.IsNodeOn(worker % 4)
+				case 3:
+					bmc.LastContact()
+					bmc.TokenInfo()
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent call failed: %v", err)
+	}
+	// One lazy authentication, and one re-authentication per forgotten token rather than one per caller
+	mu.Lock()
+	defer mu.Unlock()
+	if max := 1 + calls/25; issued > max {
+		t.Errorf("requested %d tokens for %d calls, want at most %d", issued, calls, max)
+	}
+}
+
+func TestParseBuildTime(t *testing.T) {
+	tests := []struct {
+		buildtime string
+		want      time.Time
+		wantErr   bool
+	}{
+		{buildtime: "2025-01-17 17:12:52-00:00", want: time.Date(2025, 1, 17, 17, 12, 52, 0, time.UTC)},
+		{buildtime: "2025-01-17 18:12:52+01:00", want: time.Date(2025, 1, 17, 17, 12, 52, 0, time.UTC)},
+		{buildtime: "2025-01-17T17:12:52Z", want: time.Date(2025, 1, 17, 17, 12, 52, 0, time.UTC)},
+		{buildtime: " 2025-01-17 17:12:52 ", want: time.Date(2025, 1, 17, 17, 12, 52, 0, time.UTC)},
+		{buildtime: "", wantErr: true},
+		{buildtime: "Unknown", wantErr: true},
+		{buildtime: "Fri Jan 17 2025", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.buildtime, func(t *testing.T) {
+			got, err := parseBuildTime(tt.buildtime)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseBuildTime(%q) = %v, want error", tt.buildtime, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBuildTime(%q) unexpected error: %v", tt.buildtime, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseBuildTime(%q) = %v, want %v", tt.buildtime, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBMCAPI_USBBootStatus(t *testing.T) {
+	body := `{"response":[{"result":[{"node1":0,"node2":"1","node3":false,"node4":true}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "usb_boot" || req.URL.Query().Get("opt") != "get" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	status, err := bmc.USBBootStatus()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := [4]bool{false, true, false, true}; status != want {
+		t.Errorf("USBBootStatus() = %v, want %v", status, want)
+	}
+
+	body = `{"response":[{"result":[{"node1":0,"node2":1,"node3":0}]}]}`
+	if _, err := bmc.USBBootStatus(); err == nil {
+		t.Errorf("expected error for a missing node")
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+	}))
+	if _, err := unsupported.USBBootStatus(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("USBBootStatus() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Package bmcprom exposes the state of a Turing Pi 2 board as Prometheus metrics, so a cluster can be scraped
+// through its BMC. It is a module of its own, so bmcapi stays free of dependencies.
+//
+//	prometheus.MustRegister(bmcprom.NewCollector(bmc))
+//	http.Handle("/metrics", promhttp.Handler())
+//
+// The BMC is queried on each scrape with the bmcapi read methods; nothing is polled in between. Metrics the
+// firmware does not provide are left out. The firmware reports no temperatures, so none are exported.
+package bmcprom
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/cprivitere/turing-pi2-bmc-api-sdk/bmcapi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefixes every metric name.
+const namespace = "turingpi"
+
+// Collector is a prometheus.Collector that reads the metrics from a BMC on each scrape:
+//
+//   - turingpi_up: 1 if the BMC answered the power status query, 0 otherwise
+//   - turingpi_node_power_on{node}: 1 if the node (0-3) is powered on
+//   - turingpi_node_power_watts{node}: the power draw of the node, on firmware that measures it
+//   - turingpi_fan_speed_rpm{fan}: the speed of each fan, labeled with the fan number the firmware reports, e.g. "1"
+type Collector struct {
+	bmc *bmcapi.BMCAPI
+
+	up         *prometheus.Desc
+	powerOn    *prometheus.Desc
+	powerWatts *prometheus.Desc
+	fanSpeed   *prometheus.Desc
+}
+
+// NewCollector returns a Collector for bmc. Register it with a prometheus.Registerer to export the metrics.
+func NewCollector(bmc *bmcapi.BMCAPI) *Collector {
+	return &Collector{
+		bmc: bmc,
+
+		up: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "up"),
+			"Whether the BMC answered the last scrape.", nil, nil),
+		powerOn: prometheus.NewDesc(prometheus.BuildFQName(namespace, "node", "power_on"),
+			"Whether the node is powered on.", []string{"node"}, nil),
+		powerWatts: prometheus.NewDesc(prometheus.BuildFQName(namespace, "node", "power_watts"),
+			"Power draw of the node in watts.", []string{"node"}, nil),
+		fanSpeed: prometheus.NewDesc(prometheus.BuildFQName(namespace, "fan", "speed_rpm"),
+			"Speed of the fan in revolutions per minute.", []string{"fan"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.powerOn
+	ch <- c.powerWatts
+	ch <- c.fanSpeed
+}
+
+// Collect implements prometheus.Collector. A BMC that cannot be reached is reported with turingpi_up 0 and
+// no other metrics; a failed query of an optional metric fails only that metric.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	states, err := c.bmc.PowerStates()
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+
+	for node, state := range states {
+		ch <- prometheus.MustNewConstMetric(c.powerOn, prometheus.GaugeValue, float64(state), strconv.Itoa(node))
+	}
+
+	readings, err := c.bmc.PowerReadings()
+	if err == nil {
+		for node, reading := range readings {
+			if reading.Reported {
+				ch <- prometheus.MustNewConstMetric(c.powerWatts, prometheus.GaugeValue, reading.Watts, strconv.Itoa(node))
+			}
+		}
+	} else if !errors.Is(err, bmcapi.ErrUnsupported) {
+		ch <- prometheus.NewInvalidMetric(c.powerWatts, err)
+	}
+
+	// Fans are labeled by their own number, so a fan that stops reporting does not shift the others
+	speeds, err := c.bmc.FanSpeedsByFan()
+	if err == nil {
+		for fan, rpm := range speeds {
+			ch <- prometheus.MustNewConstMetric(c.fanSpeed, prometheus.GaugeValue, float64(rpm), strconv.Itoa(fan))
+		}
+	} else if !errors.Is(err, bmcapi.ErrUnsupported) {
+		ch <- prometheus.NewInvalidMetric(c.fanSpeed, err)
+	}
+}
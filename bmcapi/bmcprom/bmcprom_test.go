@@ -0,0 +1,60 @@
+package bmcprom_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cprivitere/turing-pi2-bmc-api-sdk/bmcapi"
+	"github.com/cprivitere/turing-pi2-bmc-api-sdk/bmcapi/bmcprom"
+	"github.com/cprivitere/turing-pi2-bmc-api-sdk/bmcapi/bmctest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	mock := bmctest.NewMockBMC(bmctest.WithPower(map[string]string{"node2": "1"}))
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") == "fan_rpm" {
+			w.Write([]byte(`{"response":[{"result":[{"fan1":2400,"fan2":null,"fan3":"1800"}]}]}`))
+			return
+		}
+		mock.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	bmc, err := bmcapi.NewBMCAPI(server.URL, "basic", bmctest.DefaultUsername, bmctest.DefaultPassword, server.Client())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The mock has no power metrics, so turingpi_node_power_watts is left out, and fan2 does not report a speed
+	want := `
+# HELP turingpi_fan_speed_rpm Speed of the fan in revolutions per minute.
+# TYPE turingpi_fan_speed_rpm gauge
+turingpi_fan_speed_rpm{fan="1"} 2400
+turingpi_fan_speed_rpm{fan="3"} 1800
+# HELP turingpi_node_power_on Whether the node is powered on.
+# TYPE turingpi_node_power_on gauge
+turingpi_node_power_on{node="0"} 0
+turingpi_node_power_on{node="1"} 1
+turingpi_node_power_on{node="2"} 0
+turingpi_node_power_on{node="3"} 0
+# HELP turingpi_up Whether the BMC answered the last scrape.
+# TYPE turingpi_up gauge
+turingpi_up 1
+`
+	if err := testutil.CollectAndCompare(bmcprom.NewCollector(bmc), strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+
+	server.Close()
+	down := `
+# HELP turingpi_up Whether the BMC answered the last scrape.
+# TYPE turingpi_up gauge
+turingpi_up 0
+`
+	if err := testutil.CollectAndCompare(bmcprom.NewCollector(bmc), strings.NewReader(down)); err != nil {
+		t.Error(err)
+	}
+}
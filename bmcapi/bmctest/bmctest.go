@@ -0,0 +1,206 @@
+// Package bmctest provides an in-process mock of the Turing Pi 2 BMC API for testing code that uses bmcapi.
+//
+// The mock follows the firmware's wire format: it accepts both basic and bearer auth, keeps power and
+// USB boot state between requests, and rejects unknown request types with 400 Bad Request.
+//
+//	server := bmctest.NewMockServer(bmctest.WithPower(map[string]string{"node1": "1"}))
+//	defer server.Close()
+//	bmc, err := bmcapi.NewBMCAPI(server.URL, "basic", bmctest.DefaultUsername, bmctest.DefaultPassword, server.Client())
+//
+// To inspect the mock's state after the code under test ran, create the MockBMC with NewMockBMC
+// and serve it with httptest.NewTLSServer instead.
+package bmctest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+)
+
+const (
+	// DefaultUsername is the username accepted by a mock created without WithCredentials.
+	DefaultUsername = "root"
+	// DefaultPassword is the password accepted by a mock created without WithCredentials.
+	DefaultPassword = "turing"
+	// DefaultToken is the bearer token handed out by a mock created without WithToken.
+	DefaultToken = "bmctest-token"
+)
+
+// MockBMC is an http.Handler that serves the BMC API from canned, mutable state.
+// It is safe for concurrent use.
+type MockBMC struct {
+	mu       sync.Mutex
+	username string
+	password string
+	token    string
+	other    map[string]string
+	power    map[string]string
+	usbBoot  [4]bool
+}
+
+// Option configures a MockBMC.
+type Option func(*MockBMC)
+
+// WithCredentials sets the username and password the mock accepts.
+func WithCredentials(username, password string) Option {
+	return func(m *MockBMC) {
+		m.username = username
+		m.password = password
+	}
+}
+
+// WithToken sets the bearer token the mock hands out and accepts.
+func WithToken(token string) Option {
+	return func(m *MockBMC) {
+		m.token = token
+	}
+}
+
+// WithOther sets the object returned for opt=get&type=other.
+func WithOther(other map[string]string) Option {
+	return func(m *MockBMC) {
+		m.other = maps.Clone(other)
+	}
+}
+
+// WithPower sets the initial power state, keyed "node1" to "node4" with values "0" or "1".
+// Nodes that are not given start powered off.
+func WithPower(power map[string]string) Option {
+	return func(m *MockBMC) {
+		maps.Copy(m.power, power)
+	}
+}
+
+// NewMockBMC returns a MockBMC with default data, modified by opts.
+func NewMockBMC(opts ...Option) *MockBMC {
+	m := &MockBMC{
+		username: DefaultUsername,
+		password: DefaultPassword,
+		token:    DefaultToken,
+		other: map[string]string{
+			"api":           "1.1",
+			"build_version": "2024.05.1",
+			"buildroot":     "\"Buildroot 2024.05.1\"",
+			"buildtime":     "2025-01-17 17:12:52-00:00",
+			"ip":            "Unknown",
+			"mac":           "Unknown",
+			"version":       "2.3.4",
+		},
+		power: map[string]string{"node1": "0", "node2": "0", "node3": "0", "node4": "0"},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// NewMockServer starts an HTTPS server backed by a new MockBMC. The caller must Close it.
+// Use the server's Client method, which trusts the server's certificate, as the bmcapi client.
+func NewMockServer(opts ...Option) *httptest.Server {
+	return httptest.NewTLSServer(NewMockBMC(opts...))
+}
+
+// Power returns a copy of the current power state, keyed "node1" to "node4".
+func (m *MockBMC) Power() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.power)
+}
+
+// USBBoot reports whether the USB boot flag is set for each node (0-3).
+func (m *MockBMC) USBBoot() [4]bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usbBoot
+}
+
+// ServeHTTP implements http.Handler.
+func (m *MockBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api/bmc/authenticate":
+		m.authenticate(w, r)
+	case "/api/bmc":
+		if !m.authorized(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		m.api(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *MockBMC) authenticate(w http.ResponseWriter, r *http.Request) {
+	var creds struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if creds.Username != m.username || creds.Password != m.password {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, map[string]string{"id": m.token, "name": "bmctest", "description": "bmctest mock token"})
+}
+
+func (m *MockBMC) authorized(r *http.Request) bool {
+	if username, password, ok := r.BasicAuth(); ok {
+		return username == m.username && password == m.password
+	}
+	return r.Header.Get("Authorization") == "Bearer "+m.token
+}
+
+func (m *MockBMC) api(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	switch query.Get("opt") + "/" + query.Get("type") {
+	case "get/other":
+		writeObject(w, m.other)
+	case "get/info":
+		writeObject(w, map[string]string{})
+	case "get/power":
+		writeObject(w, m.power)
+	case "set/power":
+		for key := range m.power {
+			if value := query.Get(key); value == "0" || value == "1" {
+				m.power[key] = value
+			}
+		}
+		writeResult(w, "ok")
+	case "set/usb_boot", "set/clear_usb_boot":
+		node, err := strconv.Atoi(query.Get("node"))
+		if err != nil || node < 0 || node > 3 {
+			http.Error(w, "Parameter `node` is out of range 0..3", http.StatusBadRequest)
+			return
+		}
+		m.usbBoot[node] = query.Get("type") == "usb_boot"
+		writeResult(w, "ok")
+	default:
+		http.Error(w, "Invalid `type` parameter", http.StatusBadRequest)
+	}
+}
+
+// writeResult writes a body in the format {"response":[{"result":"<result>" }]}
+func writeResult(w http.ResponseWriter, result string) {
+	writeJSON(w, map[string]any{"response": []any{map[string]any{"result": result}}})
+}
+
+// writeObject writes a body in the format {"response":[{"result":[{<object>}] }]}
+func writeObject(w http.ResponseWriter, object map[string]string) {
+	writeJSON(w, map[string]any{"response": []any{map[string]any{"result": []any{object}}}})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
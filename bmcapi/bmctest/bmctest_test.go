@@ -0,0 +1,63 @@
+package bmctest_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cprivitere/turing-pi2-bmc-api-sdk/bmcapi"
+	"github.com/cprivitere/turing-pi2-bmc-api-sdk/bmcapi/bmctest"
+)
+
+func TestMockServer(t *testing.T) {
+	for _, authType := range []string{"basic", "bearer"} {
+		t.Run(authType, func(t *testing.T) {
+			mock := bmctest.NewMockBMC(bmctest.WithPower(map[string]string{"node2": "1"}))
+			server := httptest.NewTLSServer(mock)
+			defer server.Close()
+
+			bmc, err := bmcapi.NewBMCAPI(server.URL, authType, bmctest.DefaultUsername, bmctest.DefaultPassword, server.Client())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			other, err := bmc.Other()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if other.Version != "2.3.4" {
+				t.Errorf("Other().Version = %q, want %q", other.Version, "2.3.4")
+			}
+
+			if on, err := bmc.IsNodeOn(1); err != nil || !on {
+				t.Errorf("IsNodeOn(1) = %v, %v, want true", on, err)
+			}
+
+			// Node 0 is the firmware's node1 both when setting and when reading power
+			if _, err := bmc.SetPowerResult(0, 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := mock.Power(); got["node1"] != "1" {
+				t.Errorf("power after SetPowerResult(0, 1) = %v, want node1 on", got)
+			}
+			if states, err := bmc.PowerStates(); err != nil || states != [4]bmcapi.PowerState{bmcapi.PowerOn, bmcapi.PowerOn, bmcapi.PowerOff, bmcapi.PowerOff} {
+				t.Errorf("PowerStates() = %v, %v", states, err)
+			}
+
+			if _, err := bmc.USBBoot(3); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := mock.USBBoot(); !got[3] {
+				t.Errorf("USB boot flags = %v, want node 3 set", got)
+			}
+		})
+	}
+}
+
+func TestMockServer_RejectsBadCredentials(t *testing.T) {
+	server := bmctest.NewMockServer(bmctest.WithCredentials("admin", "hunter2"))
+	defer server.Close()
+
+	if _, err := bmcapi.NewBMCAPI(server.URL, "basic", bmctest.DefaultUsername, bmctest.DefaultPassword, server.Client()); err == nil {
+		t.Errorf("expected authentication to fail with the wrong credentials")
+	}
+}
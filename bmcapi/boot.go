@@ -0,0 +1,55 @@
+package bmcapi
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// BootSource is a storage device or interface a node boots from.
+type BootSource int
+
+// The boot sources SetBootSource accepts.
+const (
+	BootSourceEMMC BootSource = iota
+	BootSourceSD
+	BootSourceUSB
+	BootSourceNetwork
+)
+
+// bootSourceNames are the names the firmware uses for each BootSource.
+var bootSourceNames = map[BootSource]string{
+	BootSourceEMMC:    "emmc",
+	BootSourceSD:      "sd",
+	BootSourceUSB:     "usb",
+	BootSourceNetwork: "network",
+}
+
+// String returns the firmware's name for s, e.g. "emmc".
+func (s BootSource) String() string {
+	if name, ok := bootSourceNames[s]; ok {
+		return name
+	}
+	return "BootSource(" + strconv.Itoa(int(s)) + ")"
+}
+
+// SetBootSource sets the storage device or interface the specified node (0-3) boots from by default.
+// Which sources work depends on the compute module. The stock firmware cannot change a node's boot
+// source and returns ErrUnsupported; to boot a node from USB once, use USBBootResult instead.
+func (b *BMCAPI) SetBootSource(node int, source BootSource) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+	// Validate source
+	name, ok := bootSourceNames[source]
+	if !ok {
+		return SetResult{}, fmt.Errorf("invalid boot source %s", source)
+	}
+
+	bodyBytes, err := b.capabilityAPICall("boot source", "/api/bmc?opt=set&type=boot_source&node="+Node(node).queryValue()+"&source="+name)
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during Set Boot Source call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
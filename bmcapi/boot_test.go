@@ -0,0 +1,36 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_SetBootSource(t *testing.T) {
+	supported := true
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if !supported {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		query := req.URL.Query()
+		if query.Get("type") != "boot_source" || query.Get("node") != "1" || query.Get("source") != "usb" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if result, err := bmc.SetBootSource(1, BootSourceUSB); err != nil || !result.Ok() {
+		t.Errorf("SetBootSource() = %+v, %v, want ok", result, err)
+	}
+	if _, err := bmc.SetBootSource(1, BootSource(7)); err == nil {
+		t.Errorf("expected error for an invalid boot source")
+	}
+	if _, err := bmc.SetBootSource(4, BootSourceSD); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("SetBootSource(4) error = %v, want ErrInvalidNode", err)
+	}
+
+	supported = false
+	if _, err := bmc.SetBootSource(1, BootSourceUSB); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBootSource() error = %v, want ErrUnsupported", err)
+	}
+}
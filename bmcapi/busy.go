@@ -0,0 +1,36 @@
+package bmcapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// busyMessages are lowercase fragments of the messages the firmware answers with while another operation,
+// usually flashing a node, holds the hardware, e.g. "another flashing operation is in progress".
+var busyMessages = []string{"in progress", "busy"}
+
+// isBusyMessage reports whether message is one of the firmware's busy messages.
+func isBusyMessage(message string) bool {
+	message = strings.ToLower(message)
+	for _, busy := range busyMessages {
+		if strings.Contains(message, busy) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpError is a helper function that returns the error for a response with a status other than 200 OK.
+// Busy responses, 503 Service Unavailable or a busy message in the body, are reported as ErrBusy wrapping the *HTTPError.
+func httpError(resp *http.Response, bodyBytes []byte) error {
+
+	err := &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	if resp.StatusCode == http.StatusServiceUnavailable || isBusyMessage(string(bytes.TrimSpace(bodyBytes))) {
+		return fmt.Errorf("%w: %w", ErrBusy, err)
+	}
+
+	return err
+
+}
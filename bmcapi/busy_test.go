@@ -0,0 +1,48 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_ErrBusy(t *testing.T) {
+	var response *http.Response
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return response, nil
+	}))
+
+	response = mockResponse(http.StatusBadRequest, "another flashing operation is in progress")
+	_, err := bmc.SetPowerResult(1, 1)
+	var httpErr *HTTPError
+	if !errors.Is(err, ErrBusy) || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("SetPowerResult() while flashing error = %v, want ErrBusy with the HTTPError", err)
+	}
+
+	// Capability endpoints must not mistake a busy BMC for old firmware
+	response = mockResponse(http.StatusBadRequest, "another flashing operation is in progress")
+	if _, err := bmc.SetBootSource(1, BootSourceUSB); !errors.Is(err, ErrBusy) || errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBootSource() while flashing error = %v, want ErrBusy", err)
+	}
+
+	response = mockResponse(http.StatusServiceUnavailable, "")
+	if _, err := bmc.ResetNodeResult(0); !errors.Is(err, ErrBusy) {
+		t.Errorf("ResetNodeResult() on 503 error = %v, want ErrBusy", err)
+	}
+
+	response = mockResponse(http.StatusOK, `{"response":[{"result":"Device busy"}]}`)
+	_, err = bmc.USBBootResult(2)
+	var apiErr *APIError
+	if !errors.Is(err, ErrBusy) || !errors.As(err, &apiErr) {
+		t.Errorf("USBBootResult() with a busy result error = %v, want ErrBusy with the APIError", err)
+	}
+
+	response = mockResponse(http.StatusInternalServerError, "internal error")
+	if _, err := bmc.SetPowerResult(1, 1); errors.Is(err, ErrBusy) {
+		t.Errorf("SetPowerResult() on a plain failure error = %v, want no ErrBusy", err)
+	}
+	response = mockResponse(http.StatusOK, `{"response":[{"result":"invalid node"}]}`)
+	if _, err := bmc.USBBootResult(2); errors.Is(err, ErrBusy) {
+		t.Errorf("USBBootResult() with a rejected request error = %v, want no ErrBusy", err)
+	}
+}
@@ -0,0 +1,111 @@
+package bmcapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// cachedTypes are the endpoint types WithCache caches. They report details that only change when the
+// firmware is updated or a module is swapped: Other, Info, NodeInfo (and ModuleTypes, which reads it) and HardwareInfo.
+// Power, UART and flash status change from one moment to the next and are never cached.
+var cachedTypes = map[string]bool{
+	"other":         true,
+	"info":          true,
+	"node_info":     true,
+	"hardware_info": true,
+}
+
+// WithCache caches the responses of Other, Info, NodeInfo (and so ModuleTypes) and HardwareInfo for ttl, so a
+// dashboard polling them gets the cached data instead of a request to the BMC each time. Failed calls are not cached.
+// Every write request clears the cache, as does InvalidateCache. Power and UART reads are never cached, and
+// Validate always asks the BMC.
+// The cache is safe for concurrent use; a Clone starts with an empty one.
+func WithCache(ttl time.Duration) Option {
+	return func(b *BMCAPI) error {
+		if ttl <= 0 {
+			return fmt.Errorf("cache TTL must be positive")
+		}
+		b.cache = newResponseCache(ttl)
+		return nil
+	}
+}
+
+// InvalidateCache drops the responses cached with WithCache, so the next call of each cached method asks the BMC.
+// It does nothing without WithCache.
+func (b *BMCAPI) InvalidateCache() {
+	if b.cache != nil {
+		b.cache.invalidate()
+	}
+}
+
+// responseCache holds the responses of cached endpoints, keyed by endpoint.
+type responseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is a cached response. The response is copied on the way in and out, so callers cannot change it.
+type cacheEntry struct {
+	body    []byte
+	resp    http.Response
+	expires time.Time
+}
+
+// newResponseCache returns an empty cache that keeps responses for ttl.
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// cacheable reports whether the response to req may be cached, or answered from the cache.
+// Requests that must not re-authenticate, those of Validate, check the session and always reach the BMC.
+func cacheable(req *http.Request) bool {
+	if req.Context().Value(noReauthKey{}) != nil {
+		return false
+	}
+
+	query := req.URL.Query()
+	return query.Get("opt") == "get" && cachedTypes[query.Get("type")]
+}
+
+// get returns the cached response for endpoint unless it is missing or has expired.
+func (c *responseCache) get(endpoint string, req *http.Request) ([]byte, *http.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[endpoint]
+	if !ok || !time.Now().Before(entry.expires) {
+		delete(c.entries, endpoint)
+		return nil, nil, false
+	}
+
+	resp := entry.resp
+	resp.Header = entry.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(entry.body))
+	resp.Request = req
+	return bytes.Clone(entry.body), &resp, true
+}
+
+// put caches the response for endpoint.
+func (c *responseCache) put(endpoint string, body []byte, resp *http.Response) {
+	entry := cacheEntry{body: bytes.Clone(body), resp: *resp, expires: time.Now().Add(c.ttl)}
+	entry.resp.Header = resp.Header.Clone()
+	entry.resp.Body = nil
+	entry.resp.Request = nil
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[endpoint] = entry
+}
+
+// invalidate drops every cached response.
+func (c *responseCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
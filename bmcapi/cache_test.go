@@ -0,0 +1,98 @@
+package bmcapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_WithCache(t *testing.T) {
+	requests := map[string]int{}
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		requests[query.Get("opt")+" "+query.Get("type")]++
+		switch query.Get("type") {
+		case "other":
+			return mockResponse(http.StatusOK, `{"response":[{"result":[{"version":"2.0.5"}]}]}`), nil
+		case "power":
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+	if err := WithCache(time.Hour)(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 3 {
+		other, resp, err := bmc.OtherWithResponse()
+		if err != nil || other.Version != "2.0.5" || resp.StatusCode != http.StatusOK {
+			t.Fatalf("OtherWithResponse() = %+v, %v, %v", other, resp, err)
+		}
+		if _, err := bmc.GetPower(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if requests["get other"] != 1 || requests["get power"] != 3 {
+		t.Errorf("requests = %v, want one other and three power requests", requests)
+	}
+
+	bmc.InvalidateCache()
+	bmc.Other()
+	if requests["get other"] != 2 {
+		t.Errorf("Other() after InvalidateCache made %d requests in total, want 2", requests["get other"])
+	}
+
+	// A write may change what the BMC reports
+	if _, err := bmc.ReloadBMC(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bmc.Other()
+	if requests["get other"] != 3 {
+		t.Errorf("Other() after a write made %d requests in total, want 3", requests["get other"])
+	}
+
+	expiring := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests["expiring"]++
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"version":"2.0.5"}]}]}`), nil
+	}))
+	WithCache(time.Nanosecond)(expiring)
+	expiring.Other()
+	time.Sleep(time.Millisecond)
+	expiring.Other()
+	if requests["expiring"] != 2 {
+		t.Errorf("Other() after the TTL made %d requests in total, want 2", requests["expiring"])
+	}
+
+	if err := WithCache(0)(expiring); err == nil {
+		t.Errorf("expected error for a zero TTL")
+	}
+}
+
+func TestBMCAPI_WithCache_Validate(t *testing.T) {
+	password := "pass"
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if _, got, _ := req.BasicAuth(); got != password {
+			return mockResponse(http.StatusUnauthorized, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"api":"1.1"}]}]}`), nil
+	}))
+	if err := WithCache(time.Hour)(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := bmc.bmcAPICall(infoEndpoint); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if valid, err := bmc.Validate(); !valid || err != nil {
+		t.Errorf("Validate() = %v, %v, want true", valid, err)
+	}
+
+	// The password is changed on the BMC while the info response is cached
+	password = "changed"
+	if valid, err := bmc.Validate(); valid || err != nil {
+		t.Errorf("Validate() after the password changed = %v, %v, want false", valid, err)
+	}
+	if _, err := bmc.bmcAPICall(infoEndpoint); err != nil {
+		t.Errorf("cached info call error = %v, want the cached response", err)
+	}
+}
@@ -0,0 +1,119 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetTime returns the BMC's current clock. Without an RTC battery or a reachable NTP server the clock is wrong
+// after every boot, which throws off build time comparisons and token expiry.
+// Firmware that does not report its clock returns ErrUnsupported.
+func (b *BMCAPI) GetTime() (time.Time, error) {
+	bodyBytes, err := b.capabilityAPICall("BMC time", "/api/bmc?opt=get&type=time")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error during Get Time call: %w", err)
+	}
+
+	// The time is reported as {"response":[{"result":[{"time":<unix seconds>}] }]}
+	// or as a string, e.g. "2025-01-17 17:12:52-00:00"
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing time response: %w", err)
+	}
+	raw, ok := result["time"]
+	if !ok {
+		return time.Time{}, fmt.Errorf("time response has no time (body: %s)", bodySnippet(bodyBytes))
+	}
+
+	var seconds json.Number
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return parseUnixTime(seconds.String())
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return time.Time{}, fmt.Errorf("unexpected time %s", raw)
+	}
+	if t, err := parseUnixTime(text); err == nil {
+		return t, nil
+	}
+
+	return parseBuildTime(text)
+}
+
+// parseUnixTime is a helper function that parses value as seconds since the Unix epoch.
+func parseUnixTime(value string) (time.Time, error) {
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, fmt.Errorf("invalid Unix time %q", value)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+
+}
+
+// SetNTPServer makes the BMC synchronize its clock with server, a host name or IP address with an optional
+// port, e.g. "pool.ntp.org" or "192.168.1.1:123". On an offline cluster this is typically a local router or one
+// of the nodes. Firmware that does not let its NTP server be set returns ErrUnsupported.
+func (b *BMCAPI) SetNTPServer(server string) (SetResult, error) {
+	// Validate server
+	if err := validateNTPServer(server); err != nil {
+		return SetResult{}, err
+	}
+
+	bodyBytes, err := b.capabilityAPICall("NTP server", "/api/bmc?opt=set&type=ntp&server="+url.QueryEscape(server))
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during Set NTP Server call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
+
+// validateNTPServer checks that server is a host name or IP address, with an optional port.
+func validateNTPServer(server string) error {
+
+	host := server
+	if h, port, err := net.SplitHostPort(server); err == nil {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("invalid port in NTP server %q", server)
+		}
+		host = h
+	}
+
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if !validHostname(host) {
+		return fmt.Errorf("NTP server %q is not a host name or IP address", server)
+	}
+
+	return nil
+
+}
+
+// validHostname reports whether host is a valid DNS host name: dot-separated labels of letters, digits and
+// hyphens, each 1-63 characters long and not starting or ending with a hyphen.
+func validHostname(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
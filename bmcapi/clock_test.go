@@ -0,0 +1,75 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_GetTime(t *testing.T) {
+	want := time.Date(2025, 1, 17, 17, 12, 52, 0, time.UTC)
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unix seconds", `{"response":[{"result":[{"time":1737133972}]}]}`},
+		{"unix seconds string", `{"response":[{"result":[{"time":"1737133972"}]}]}`},
+		{"formatted", `{"response":[{"result":[{"time":"2025-01-17 17:12:52-00:00"}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Query().Get("type") != "time" {
+					return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+				}
+				return mockResponse(http.StatusOK, tt.body), nil
+			}))
+
+			got, err := bmc.GetTime()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("GetTime() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+	}))
+	if _, err := unsupported.GetTime(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetTime() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestBMCAPI_SetNTPServer(t *testing.T) {
+	var servers []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "ntp" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		servers = append(servers, req.URL.Query().Get("server"))
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	for _, server := range []string{"pool.ntp.org", "192.168.1.1:123", "[fd00::1]:123", "fd00::1", "router"} {
+		if _, err := bmc.SetNTPServer(server); err != nil {
+			t.Errorf("SetNTPServer(%q) error = %v", server, err)
+		}
+	}
+	if len(servers) != 5 || servers[2] != "[fd00::1]:123" {
+		t.Errorf("servers sent = %v", servers)
+	}
+
+	for _, server := range []string{"", "ntp://pool.ntp.org", "pool.ntp.org:0", "-bad.example", "a b", "pool..ntp.org", "host;reboot"} {
+		if _, err := bmc.SetNTPServer(server); err == nil {
+			t.Errorf("SetNTPServer(%q) expected error", server)
+		}
+	}
+	if len(servers) != 5 {
+		t.Errorf("invalid servers were sent: %v", servers[5:])
+	}
+}
@@ -0,0 +1,79 @@
+package bmcapi
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a copy of b with opts applied on top of its configuration, e.g. a longer flash timeout with
+// WithTimeouts or another logger, without authenticating again. The copy starts with b's credentials and
+// bearer token, so both use the same session, but they keep their own copy of it afterwards: UpdateCredentials
+// or a re-established session on one does not change the other. Options that add to a list, such as
+// WithRequestInterceptor, add to the interceptors copied from b.
+func (b *BMCAPI) Clone(opts ...Option) (*BMCAPI, error) {
+
+	// Every field of BMCAPI is copied here, so keep this in sync with it
+	b.mu.RLock()
+	auth := *b.auth
+	c := &BMCAPI{
+		BaseURL:  b.BaseURL,
+		Client:   b.Client,
+		AuthType: b.AuthType,
+
+		logger:          b.logger,
+		dryRun:          b.dryRun,
+		rebootCommand:   b.rebootCommand,
+		shutdownTimeout: b.shutdownTimeout,
+		powerCycleDelay: b.powerCycleDelay,
+		lazyAuth:        b.lazyAuth,
+		timeouts:        b.timeouts,
+		bearerToken:     b.bearerToken,
+		jitter:          b.jitter,
+		protectedNodes:  maps.Clone(b.protectedNodes),
+		idempotencyKeys: b.idempotencyKeys,
+		strictTLS:       b.strictTLS,
+		flashRetries:    b.flashRetries,
+		defaultNode:     b.defaultNode,
+		hasDefaultNode:  b.hasDefaultNode,
+		forceHTTPS:      b.forceHTTPS,
+		sdCardThreshold: b.sdCardThreshold,
+
+		apiPrefix:       b.apiPrefix,
+		customAPIPrefix: b.customAPIPrefix,
+
+		primaryURL:   b.primaryURL,
+		fallbackURLs: slices.Clone(b.fallbackURLs),
+
+		defaultHeaders:       b.defaultHeaders.Clone(),
+		requestInterceptors:  slices.Clone(b.requestInterceptors),
+		responseInterceptors: slices.Clone(b.responseInterceptors),
+
+		nodeNames: maps.Clone(b.nodeNames),
+	}
+	if b.cache != nil {
+		c.cache = newResponseCache(b.cache.ttl)
+	}
+	if b.reauth != nil {
+		c.reauth = &reauthBackoff{initial: b.reauth.initial, max: b.reauth.max, maxAttempts: b.reauth.maxAttempts}
+	}
+	b.mu.RUnlock()
+	c.lastContact.Store(b.lastContact.Load())
+
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.forceHTTPS {
+		c.upgradeToHTTPS()
+	}
+
+	// A bearer token given to the clone replaces the session's
+	if c.bearerToken != b.bearerToken {
+		auth.AccessToken = c.bearerToken
+	}
+	c.auth = &auth
+
+	return c, nil
+
+}
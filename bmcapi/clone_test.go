@@ -0,0 +1,45 @@
+package bmcapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_Clone(t *testing.T) {
+	var requests int
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if req.Header.Get("Authorization") != "Bearer expired" {
+			t.Errorf("request sent with %q, want the shared session", req.Header.Get("Authorization"))
+		}
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+	if err := WithProtectedNodes(0)(bmc); err != nil {
+		t.Fatal(err)
+	}
+
+	flashing, err := bmc.Clone(WithTimeouts(Timeouts{Flash: time.Hour}), WithProtectedNodes(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Clone() made %d requests, want none", requests)
+	}
+	if _, err := flashing.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if flashing.timeouts.Flash != time.Hour || bmc.timeouts.Flash == time.Hour {
+		t.Errorf("flash timeout = %v on the clone and %v on the original", flashing.timeouts.Flash, bmc.timeouts.Flash)
+	}
+	if !bmc.protectedNodes[0] || bmc.protectedNodes[1] || !flashing.protectedNodes[1] {
+		t.Errorf("protected nodes = %v on the original and %v on the clone", bmc.protectedNodes, flashing.protectedNodes)
+	}
+
+	// The sessions are copies, not shared
+	flashing.setAuth(&bmcApiAuth{AccessToken: "other"})
+	if bmc.currentAuth().AccessToken != "expired" {
+		t.Errorf("changing the clone's session changed the original")
+	}
+}
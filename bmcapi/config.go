@@ -0,0 +1,190 @@
+package bmcapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config is the connection settings of a BMC, loaded with ConfigFromFile, so CLI tools do not need to
+// hardcode them. NewBMCAPI creates a client from it; Options returns the settings that map to options.
+type Config struct {
+	BaseURL  string `json:"base_url"`
+	AuthType string `json:"auth_type"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// Token is a bearer token to use instead of authenticating (see WithBearerToken)
+	Token string `json:"token,omitempty"`
+
+	// TLS is "insecure" to skip certificate verification (see WithInsecureTLS) or "strict" to verify
+	// certificates (see WithStrictTLS). Empty leaves the client passed to NewBMCAPI as it is.
+	TLS string `json:"tls,omitempty"`
+
+	APIPrefix    string   `json:"api_prefix,omitempty"`
+	FallbackURLs []string `json:"fallback_urls,omitempty"`
+	LazyAuth     bool     `json:"lazy_auth,omitempty"`
+
+	// Timeouts are durations such as "30s" or "15m" (see WithTimeouts)
+	Timeouts struct {
+		Read  configDuration `json:"read,omitempty"`
+		Write configDuration `json:"write,omitempty"`
+		Flash configDuration `json:"flash,omitempty"`
+	} `json:"timeouts,omitempty"`
+}
+
+// configDuration is a time.Duration written as a string such as "30s" in a config file.
+type configDuration time.Duration
+
+func (d *configDuration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(duration)
+	return nil
+}
+
+// ConfigFromFile loads connection settings from a JSON file, e.g.
+//
+//	{
+//	  "base_url": "https://turingpi.local",
+//	  "auth_type": "bearer",
+//	  "username": "root",
+//	  "password": "${TPI_PASSWORD}",
+//	  "timeouts": {"flash": "30m"}
+//	}
+//
+// References to environment variables, written ${NAME}, are replaced in every string value, so secrets can be
+// kept out of the file; a variable that is not set is an error. Any other "$" is kept as it is, so passwords may
+// contain one; write "$$" for a literal "$" followed by "{" or "$". Unknown keys are rejected to catch typos.
+// Only JSON is supported, to keep the SDK free of dependencies.
+func ConfigFromFile(path string) (*Config, error) {
+
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".json" {
+		return nil, fmt.Errorf("unsupported config format %q, only JSON is supported", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+
+	var config Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %w", path, err)
+	}
+
+	strs := []*string{&config.BaseURL, &config.AuthType, &config.Username, &config.Password, &config.Token, &config.TLS, &config.APIPrefix}
+	for i := range config.FallbackURLs {
+		strs = append(strs, &config.FallbackURLs[i])
+	}
+	for _, s := range strs {
+		if *s, err = expandEnv(*s); err != nil {
+			return nil, fmt.Errorf("error in config %s: %w", path, err)
+		}
+	}
+
+	switch config.TLS {
+	case "", "insecure", "strict":
+	default:
+		return nil, fmt.Errorf("error in config %s: tls must be \"insecure\" or \"strict\", got %q", path, config.TLS)
+	}
+
+	return &config, nil
+}
+
+// expandEnv replaces ${NAME} references to environment variables in s, failing for variables that are not set.
+// "$$" is a literal "$", and a "$" not followed by "{" or "$" is kept as it is.
+func expandEnv(s string) (string, error) {
+
+	var expanded strings.Builder
+	var missing []string
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 || i == len(s)-1 {
+			expanded.WriteString(s)
+			break
+		}
+		expanded.WriteString(s[:i])
+		s = s[i:]
+
+		switch s[1] {
+		case '$':
+			expanded.WriteByte('$')
+			s = s[2:]
+		case '{':
+			end := strings.IndexByte(s, '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated environment variable reference %q, write $$ for a literal $", s)
+			}
+			name := s[2:end]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			expanded.WriteString(value)
+			s = s[end+1:]
+		default:
+			expanded.WriteByte('$')
+			s = s[1:]
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variables %s are not set", strings.Join(missing, ", "))
+	}
+
+	return expanded.String(), nil
+
+}
+
+// Options returns the options for the settings of c other than the base URL, auth type and credentials,
+// which are arguments of NewBMCAPI.
+func (c *Config) Options() []Option {
+	var opts []Option
+
+	switch c.TLS {
+	case "insecure":
+		opts = append(opts, WithInsecureTLS())
+	case "strict":
+		opts = append(opts, WithStrictTLS())
+	}
+	if c.Token != "" {
+		opts = append(opts, WithBearerToken(c.Token))
+	}
+	if c.APIPrefix != "" {
+		opts = append(opts, WithAPIPrefix(c.APIPrefix))
+	}
+	if len(c.FallbackURLs) > 0 {
+		opts = append(opts, WithFallbackURLs(c.FallbackURLs...))
+	}
+	if c.LazyAuth {
+		opts = append(opts, WithLazyAuth())
+	}
+	if c.Timeouts.Read != 0 || c.Timeouts.Write != 0 || c.Timeouts.Flash != 0 {
+		opts = append(opts, WithTimeouts(Timeouts{
+			Read:  time.Duration(c.Timeouts.Read),
+			Write: time.Duration(c.Timeouts.Write),
+			Flash: time.Duration(c.Timeouts.Flash),
+		}))
+	}
+
+	return opts
+}
+
+// NewBMCAPI creates a BMCAPI with the settings of c, as the package level NewBMCAPI does with client.
+// opts are applied after the options from c, so they can override them.
+func (c *Config) NewBMCAPI(client *http.Client, opts ...Option) (*BMCAPI, error) {
+	return NewBMCAPI(c.BaseURL, c.AuthType, c.Username, c.Password, client, append(c.Options(), opts...)...)
+}
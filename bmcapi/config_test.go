@@ -0,0 +1,80 @@
+package bmcapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFromFile(t *testing.T) {
+	t.Setenv("TPI_PASSWORD", `pa"ss`)
+	path := writeConfig(t, "bmc.json", `{
+		"base_url": "https://10.0.0.2",
+		"auth_type": "basic",
+		"username": "root",
+		"password": "${TPI_PASSWORD}",
+		"tls": "insecure",
+		"fallback_urls": ["https://turingpi.local"],
+		"lazy_auth": true,
+		"timeouts": {"flash": "30m"}
+	}`)
+
+	config, err := ConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Password != `pa"ss` || config.Username != "root" {
+		t.Errorf("credentials = %q, %q", config.Username, config.Password)
+	}
+
+	bmc, err := config.NewBMCAPI(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bmc.BaseURL != "https://10.0.0.2" || bmc.AuthType != "basic" || len(bmc.fallbackURLs) != 1 ||
+		bmc.timeouts.Flash != 30*time.Minute || !bmc.lazyAuth || bmc.currentAuth().Password != `pa"ss` {
+		t.Errorf("client from config = %+v", bmc)
+	}
+
+	// A "$" in a password is kept unless it starts a ${NAME} reference or is escaped as "$$"
+	passwords := map[string]string{
+		`p$ss$`:               `p$ss$`,
+		`$HOME`:               `$HOME`,
+		`lit$${TPI_PASSWORD}`: `lit${TPI_PASSWORD}`,
+		`a$$$${TPI_PASSWORD}`: `a$${TPI_PASSWORD}`,
+		`x-${TPI_PASSWORD}`:   `x-pa"ss`,
+	}
+	for password, want := range passwords {
+		config, err := ConfigFromFile(writeConfig(t, "bmc.json", `{"password": "`+password+`"}`))
+		if err != nil || config.Password != want {
+			t.Errorf("password %q loaded as %+v, %v, want %q", password, config, err, want)
+		}
+	}
+
+	errorCases := map[string]string{
+		"missing variable": `{"password": "${TPI_UNSET_PASSWORD}"}`,
+		"unterminated ref": `{"password": "pa${ss"}`,
+		"unknown key":      `{"base_ulr": "https://10.0.0.2"}`,
+		"bad duration":     `{"timeouts": {"read": "soon"}}`,
+		"bad tls":          `{"tls": "maybe"}`,
+	}
+	for name, content := range errorCases {
+		if _, err := ConfigFromFile(writeConfig(t, "bmc.json", content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if _, err := ConfigFromFile(writeConfig(t, "bmc.yaml", "base_url: x")); err == nil || !strings.Contains(err.Error(), "only JSON") {
+		t.Errorf("ConfigFromFile(yaml) error = %v, want an unsupported format error", err)
+	}
+}
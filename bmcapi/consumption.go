@@ -0,0 +1,91 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PowerReading is the power draw of a single node.
+// Reported is false when the firmware gave no value for the node, in which case Watts is 0.
+type PowerReading struct {
+	Watts    float64
+	Reported bool
+}
+
+// PowerReadings returns the power draw of each node (0-3), read from the per-node power metrics
+// of firmware that measures them. The stock firmware does not expose power metrics, in which case
+// ErrUnsupported is returned.
+func (b *BMCAPI) PowerReadings() ([4]PowerReading, error) {
+	var readings [4]PowerReading
+
+	bodyBytes, err := b.capabilityAPICall("power consumption", "/api/bmc?opt=get&type=power_consumption")
+	if err != nil {
+		return readings, fmt.Errorf("error during Power Consumption call: %w", err)
+	}
+
+	var parsed bmcPowerAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return readings, fmt.Errorf("error parsing json in power consumption response: %w", err)
+	}
+	if len(parsed.Response) == 0 || len(parsed.Response[0].Result) == 0 {
+		return readings, fmt.Errorf("no data in response")
+	}
+
+	result := parsed.Response[0].Result[0]
+	for node := range readings {
+		key := Node(node).key()
+		raw, ok := result[key]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		watts, err := parseWatts(raw)
+		if err != nil {
+			return readings, fmt.Errorf("%s: %w", key, err)
+		}
+		readings[node] = PowerReading{Watts: watts, Reported: true}
+	}
+
+	return readings, nil
+}
+
+// PowerConsumption returns the power draw of each node (0-3) in watts.
+// Nodes the firmware reports no value for read as 0; use PowerReadings to tell them apart.
+func (b *BMCAPI) PowerConsumption() ([4]float64, error) {
+	var watts [4]float64
+
+	readings, err := b.PowerReadings()
+	if err != nil {
+		return watts, err
+	}
+	for node, reading := range readings {
+		watts[node] = reading.Watts
+	}
+
+	return watts, nil
+}
+
+// parseWatts converts a JSON number or numeric string such as "4.2" or " 4.2 W" into watts.
+func parseWatts(raw json.RawMessage) (float64, error) {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, fmt.Errorf("invalid power reading %s: %w", raw, err)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		text := strings.TrimSpace(v)
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "W"), "w"))
+		watts, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid power reading %q", v)
+		}
+		return watts, nil
+	}
+
+	return 0, fmt.Errorf("invalid power reading %s", raw)
+}
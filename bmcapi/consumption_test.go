@@ -0,0 +1,40 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_PowerReadings(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"node1":"4.5","node2":" 7 W ","node3":3.25}]}]}`), nil
+	}))
+
+	got, err := bmc.PowerReadings()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [4]PowerReading{{4.5, true}, {7, true}, {3.25, true}, {0, false}}
+	if got != want {
+		t.Errorf("PowerReadings() = %v, want %v", got, want)
+	}
+
+	watts, err := bmc.PowerConsumption()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if watts != [4]float64{4.5, 7, 3.25, 0} {
+		t.Errorf("PowerConsumption() = %v", watts)
+	}
+}
+
+func TestBMCAPI_PowerConsumption_Unsupported(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+	}))
+
+	if _, err := bmc.PowerConsumption(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("PowerConsumption() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,143 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CoolingDevice is a fan or other cooling device reported by the BMC.
+// Speed and MaxSpeed are cooling levels as defined by the device, with 0 meaning off.
+type CoolingDevice struct {
+	Device   string `json:"device"`
+	Speed    int    `json:"speed"`
+	MaxSpeed int    `json:"max_speed"`
+}
+
+// bmcCoolingAPIResponse is a struct that represents the response from the BMC API for the cooling endpoint.
+// It expects the response to be in the format {"response":[{"result":[{"device":"<name>","speed":<n>,"max_speed":<n>}] }]}
+type bmcCoolingAPIResponse struct {
+	Response []struct {
+		Result []CoolingDevice `json:"result"`
+	} `json:"response"`
+}
+
+// GetCooling returns the cooling devices of the board with their current and maximum levels.
+// The firmware does not report temperatures on this endpoint.
+// Firmware released before cooling control was added returns ErrUnsupported.
+func (b *BMCAPI) GetCooling() ([]CoolingDevice, error) {
+	bodyBytes, err := b.capabilityAPICall("cooling", "/api/bmc?opt=get&type=cooling")
+	if err != nil {
+		return nil, fmt.Errorf("error during Get Cooling call: %w", err)
+	}
+
+	var parsed bmcCoolingAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing json in cooling response: %w", err)
+	}
+	if len(parsed.Response) == 0 {
+		return nil, fmt.Errorf("no data in response")
+	}
+
+	return parsed.Response[0].Result, nil
+}
+
+// SetFanSpeed sets every cooling device of the board to percent (0-100) of its maximum level.
+// Firmware released before cooling control was added returns ErrUnsupported.
+func (b *BMCAPI) SetFanSpeed(percent int) error {
+	// Validate percent
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("percent must be between 0 and 100")
+	}
+
+	devices, err := b.GetCooling()
+	if err != nil {
+		return err
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("BMC reports no cooling devices")
+	}
+
+	for _, device := range devices {
+		speed := int(math.Round(float64(percent) * float64(device.MaxSpeed) / 100))
+
+		bodyBytes, err := b.capabilityAPICall("cooling", "/api/bmc?opt=set&type=cooling&device="+url.QueryEscape(device.Device)+"&speed="+strconv.Itoa(speed))
+		if err != nil {
+			return fmt.Errorf("error during Set Cooling call for %s: %w", device.Device, err)
+		}
+
+		if _, err := b.resultAPIParse(bodyBytes); err != nil {
+			return fmt.Errorf("error setting speed of %s: %w", device.Device, err)
+		}
+	}
+
+	return nil
+}
+
+// FanSpeeds returns the measured speed of each fan in RPM, ordered by fan number. Fans the board does not report,
+// e.g. an empty fan header, are skipped; a fan reporting 0 RPM is included, as it usually means the fan has stalled.
+// As skipped fans shift the ones after them, use FanSpeedsByFan to tell the fans apart.
+// Firmware that does not report fan RPMs returns ErrUnsupported.
+func (b *BMCAPI) FanSpeeds() ([]int, error) {
+	rpms, err := b.FanSpeedsByFan()
+	if err != nil {
+		return nil, err
+	}
+
+	fans := slices.Sorted(maps.Keys(rpms))
+	speeds := make([]int, len(fans))
+	for i, fan := range fans {
+		speeds[i] = rpms[fan]
+	}
+
+	return speeds, nil
+}
+
+// FanSpeedsByFan returns the measured speed of each fan in RPM, keyed by the fan number the firmware reports it
+// under, e.g. 1 for "fan1". Fans the board does not report are left out, as in FanSpeeds.
+// Firmware that does not report fan RPMs returns ErrUnsupported.
+func (b *BMCAPI) FanSpeedsByFan() (map[int]int, error) {
+	bodyBytes, err := b.capabilityAPICall("fan speeds", "/api/bmc?opt=get&type=fan_rpm")
+	if err != nil {
+		return nil, fmt.Errorf("error during Fan Speeds call: %w", err)
+	}
+
+	// The speeds are reported as {"response":[{"result":[{"fan1":<rpm>,"fan2":"<rpm>", ...}] }]}
+	// where absent fans are null or ""
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing fan speeds response: %w", err)
+	}
+
+	rpms := make(map[int]int, len(result))
+	for key, raw := range result {
+		number, ok := strings.CutPrefix(key, "fan")
+		fan, err := strconv.Atoi(number)
+		if !ok || err != nil {
+			continue
+		}
+
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			text = string(raw)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || text == "null" {
+			continue
+		}
+		rpm, err := strconv.ParseFloat(text, 64)
+		if err != nil || rpm < 0 {
+			return nil, fmt.Errorf("invalid speed %q for %s", text, key)
+		}
+
+		rpms[fan] = int(math.Round(rpm))
+	}
+
+	return rpms, nil
+}
@@ -0,0 +1,89 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestBMCAPI_SetFanSpeed(t *testing.T) {
+	var setQueries []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("type") != "cooling" {
+			return mockResponse(http.StatusNotFound, ""), nil
+		}
+		if query.Get("opt") == "set" {
+			setQueries = append(setQueries, query.Get("device")+"="+query.Get("speed"))
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"device":"fan0","speed":2,"max_speed":4},{"device":"fan1","speed":0,"max_speed":10}]}]}`), nil
+	}))
+
+	devices, err := bmc.GetCooling()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(devices) != 2 || devices[0] != (CoolingDevice{Device: "fan0", Speed: 2, MaxSpeed: 4}) {
+		t.Errorf("GetCooling() = %+v", devices)
+	}
+
+	if err := bmc.SetFanSpeed(50); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(setQueries) != 2 || setQueries[0] != "fan0=2" || setQueries[1] != "fan1=5" {
+		t.Errorf("set requests = %v, want [fan0=2 fan1=5]", setQueries)
+	}
+
+	if err := bmc.SetFanSpeed(101); err == nil {
+		t.Errorf("expected error for percent above 100")
+	}
+}
+
+func TestBMCAPI_GetCooling_Unsupported(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+	}))
+
+	if _, err := bmc.GetCooling(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetCooling() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestBMCAPI_FanSpeeds(t *testing.T) {
+	body := `{"response":[{"result":[{"fan2":"0","fan1":2400,"fan3":null,"fan4":"","fan10":"1800.4"}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "fan_rpm" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	speeds, err := bmc.FanSpeeds()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{2400, 0, 1800}; !reflect.DeepEqual(speeds, want) {
+		t.Errorf("FanSpeeds() = %v, want %v", speeds, want)
+	}
+	byFan, err := bmc.FanSpeedsByFan()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[int]int{1: 2400, 2: 0, 10: 1800}; !reflect.DeepEqual(byFan, want) {
+		t.Errorf("FanSpeedsByFan() = %v, want %v", byFan, want)
+	}
+
+	body = `{"response":[{"result":[{"fan1":"fast"}]}]}`
+	if _, err := bmc.FanSpeeds(); err == nil {
+		t.Errorf("expected error for a non-numeric speed")
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusNotFound, ""), nil
+	}))
+	if _, err := unsupported.FanSpeeds(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("FanSpeeds() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,59 @@
+package bmcapi
+
+// WithDefaultNode sets the node (0-3) the Default methods, such as PowerOnDefault and UARTDefault, operate on,
+// for scripts that mostly work with a single node. Without it those methods return ErrNoDefaultNode.
+func WithDefaultNode(node int) Option {
+	return func(b *BMCAPI) error {
+		// Validate node number
+		if !Node(node).Valid() {
+			return ErrInvalidNode
+		}
+		b.defaultNode = node
+		b.hasDefaultNode = true
+		return nil
+	}
+}
+
+// DefaultNode returns the node set with WithDefaultNode, or ErrNoDefaultNode if none was set.
+func (b *BMCAPI) DefaultNode() (int, error) {
+	if !b.hasDefaultNode {
+		return 0, ErrNoDefaultNode
+	}
+	return b.defaultNode, nil
+}
+
+// PowerOnDefault powers on the default node, see WithDefaultNode and SetNodePower.
+func (b *BMCAPI) PowerOnDefault() (SetResult, error) {
+	node, err := b.DefaultNode()
+	if err != nil {
+		return SetResult{}, err
+	}
+	return b.SetNodePower(node, PowerOn)
+}
+
+// PowerOffDefault powers off the default node, see WithDefaultNode and SetNodePower.
+func (b *BMCAPI) PowerOffDefault() (SetResult, error) {
+	node, err := b.DefaultNode()
+	if err != nil {
+		return SetResult{}, err
+	}
+	return b.SetNodePower(node, PowerOff)
+}
+
+// UARTDefault returns the serial console output of the default node, see WithDefaultNode and GetUART.
+func (b *BMCAPI) UARTDefault() (string, error) {
+	node, err := b.DefaultNode()
+	if err != nil {
+		return "", err
+	}
+	return b.GetUART(node)
+}
+
+// SetUARTDefault sends cmd to the serial console of the default node, see WithDefaultNode and SetUARTResult.
+func (b *BMCAPI) SetUARTDefault(cmd string) (SetResult, error) {
+	node, err := b.DefaultNode()
+	if err != nil {
+		return SetResult{}, err
+	}
+	return b.SetUARTResult(node, cmd)
+}
@@ -0,0 +1,48 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_DefaultNode(t *testing.T) {
+	var queries []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		queries = append(queries, req.URL.RawQuery)
+		if req.URL.Query().Get("type") == "uart" && req.URL.Query().Get("opt") == "get" {
+			return mockResponse(http.StatusOK, uartResponse("login: ")), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if _, err := bmc.PowerOnDefault(); !errors.Is(err, ErrNoDefaultNode) {
+		t.Errorf("PowerOnDefault() without a default node error = %v, want ErrNoDefaultNode", err)
+	}
+	if _, err := bmc.UARTDefault(); !errors.Is(err, ErrNoDefaultNode) {
+		t.Errorf("UARTDefault() without a default node error = %v, want ErrNoDefaultNode", err)
+	}
+	if len(queries) != 0 {
+		t.Fatalf("sent %v without a default node", queries)
+	}
+
+	if err := WithDefaultNode(2)(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.PowerOnDefault(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "opt=set&type=power&node3=1"; queries[0] != want {
+		t.Errorf("PowerOnDefault() sent %s, want %s", queries[0], want)
+	}
+	if output, err := bmc.UARTDefault(); err != nil || output != "login: " {
+		t.Errorf("UARTDefault() = %q, %v, want the console of node 2", output, err)
+	}
+	if want := "opt=get&type=uart&node=2"; queries[1] != want {
+		t.Errorf("UARTDefault() sent %s, want %s", queries[1], want)
+	}
+
+	if err := WithDefaultNode(4)(bmc); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("WithDefaultNode(4) error = %v, want ErrInvalidNode", err)
+	}
+}
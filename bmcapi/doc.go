@@ -0,0 +1,19 @@
+// Package bmcapi is a client for the HTTP API of the Turing Pi 2 board management controller (BMC).
+//
+// Create a client with NewBMCAPI and configure it with Options:
+//
+//	bmc, err := bmcapi.NewBMCAPI("https://turingpi.local", "bearer", username, password, nil)
+//
+// # Tracing
+//
+// Every request is made with http.NewRequestWithContext. Methods that take a context.Context, such as
+// FlashNodeResult, WaitForNodePower and StreamUART, make all their requests under it, so trace context
+// set on it propagates; the other methods use context.Background(). To record a span for each request,
+// wrap the client's transport, e.g. with go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp:
+//
+//	client := bmcapi.NewInsecureClient()
+//	client.Transport = otelhttp.NewTransport(client.Transport)
+//	bmc, err := bmcapi.NewBMCAPI(baseURL, "bearer", username, password, client)
+//
+// WithResponseInterceptor is a lighter alternative when only latency and status codes are needed.
+package bmcapi
@@ -0,0 +1,46 @@
+package bmcapi
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithDryRun(t *testing.T) {
+	var sent []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.URL.RawQuery)
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	var logs bytes.Buffer
+	if err := WithDryRun(slog.New(slog.NewTextHandler(&logs, nil)))(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := bmc.SetPower(2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != "ok" {
+		t.Errorf("SetPower() result = %q, want %q", *result, "ok")
+	}
+	if _, err := bmc.USBBoot(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("write requests were sent in dry-run mode: %v", sent)
+	}
+	if !strings.Contains(logs.String(), "type=usb_boot") {
+		t.Errorf("expected the skipped request to be logged, got:\n%s", logs.String())
+	}
+
+	if _, err := bmc.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Errorf("read request was not sent in dry-run mode")
+	}
+}
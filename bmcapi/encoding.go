@@ -0,0 +1,64 @@
+package bmcapi
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is the Accept-Encoding header sent with API requests. Setting it turns off the transparent
+// gzip decoding of http.Transport, so responses are decoded by decodeBody for every transport alike.
+const acceptEncoding = "gzip, deflate"
+
+// decodeBody returns a reader of resp's body decoded according to its Content-Encoding. Bodies without an
+// encoding, or already decoded by the transport, are returned as they are. Deflate is accepted both in the
+// zlib format HTTP prescribes and as the raw stream some servers send.
+func decodeBody(resp *http.Response) (io.ReadCloser, error) {
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding gzip response: %w", err)
+		}
+		return reader, nil
+	case "deflate":
+		buffered := bufio.NewReader(resp.Body)
+		header, err := buffered.Peek(2)
+		if err == nil && len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			reader, err := zlib.NewReader(buffered)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding deflate response: %w", err)
+			}
+			return reader, nil
+		}
+		return flate.NewReader(buffered), nil
+	}
+
+	return nil, fmt.Errorf("unsupported response encoding %q", encoding)
+
+}
+
+// readBody reads the whole body of resp, decoded according to its Content-Encoding.
+func readBody(resp *http.Response) ([]byte, error) {
+
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(body)
+	return buf.Bytes(), err
+
+}
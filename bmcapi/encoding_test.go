@@ -0,0 +1,50 @@
+package bmcapi
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBMCAPI_CompressedResponse(t *testing.T) {
+	console := strings.Repeat("[    1.000000] a long boot log line\n", 1000)
+	plain := uartResponse(console)
+
+	compressors := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw deflate": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+	}
+	for name, compress := range compressors {
+		var compressed bytes.Buffer
+		w := compress(&compressed)
+		io.WriteString(w, plain)
+		w.Close()
+
+		bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+			if !strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") {
+				t.Errorf("%s: request sent without Accept-Encoding", name)
+			}
+			resp := mockResponse(http.StatusOK, compressed.String())
+			resp.Header.Set("Content-Encoding", strings.TrimPrefix(name, "raw "))
+			return resp, nil
+		}))
+
+		got, err := bmc.GetUART(0)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if got != console {
+			t.Errorf("%s: GetUART() returned %d bytes, want %d", name, len(got), len(console))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package bmcapi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidNode is returned when a node number outside 0-3 is given.
+var ErrInvalidNode = errors.New("node number must be between 0 and 3")
+
+// ErrInvalidPowerState is returned when a power state other than 0 (off) or 1 (on) is given.
+var ErrInvalidPowerState = errors.New("powerState must be 0 (off) or 1 (on)")
+
+// ErrUnsupported is returned when the connected firmware does not provide the requested feature.
+var ErrUnsupported = errors.New("not supported by this BMC firmware")
+
+// ErrNonJSONResponse is returned when the BMC answers with something other than JSON, typically the HTML login page it serves when the session is no longer valid.
+var ErrNonJSONResponse = errors.New("BMC returned non-JSON response, session may be invalid")
+
+// ErrReauthFailed is returned when a bearer session was rejected and no new token could be requested (see WithReauthBackoff).
+var ErrReauthFailed = errors.New("re-authentication failed")
+
+// ErrProtectedNode is returned when powering off a node protected with WithProtectedNodes without forcing it.
+var ErrProtectedNode = errors.New("node is protected from power off")
+
+// ErrNoDefaultNode is returned by the Default methods, such as PowerOnDefault, when no node was set with WithDefaultNode.
+var ErrNoDefaultNode = errors.New("no default node set")
+
+// ErrBusy is returned when the BMC refuses a request because another operation is in progress, usually
+// flashing a node. The request can be retried once the operation has finished, e.g. after WaitForFlash.
+// The error also matches the *HTTPError or *APIError the firmware's answer was reported with.
+var ErrBusy = errors.New("BMC is busy with another operation")
+
+// ErrTruncatedResponse is returned when a response body ends in the middle of its JSON, usually because the
+// connection to the BMC dropped while it was answering. The request can be retried.
+var ErrTruncatedResponse = errors.New("BMC response was truncated, the connection may have dropped; retry the request")
+
+// HTTPError is returned when the BMC answers a request with a status other than 200 OK.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("http error in response: %s", e.Status)
+}
+
+// APIError is returned when the BMC answers a set request with 200 OK but reports a result other than "ok",
+// which the firmware uses for requests it rejected. Result is the firmware's message.
+type APIError struct {
+	Result string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("BMC rejected request: %s", e.Result)
+}
+
+// Is reports whether the firmware rejected the request because it is busy, so errors.Is(err, ErrBusy) matches it.
+func (e *APIError) Is(target error) bool {
+	return target == ErrBusy && isBusyMessage(e.Result)
+}
+
+// FlashUploadError is returned by FlashNodeResult when the connection to the BMC failed while the image was
+// uploaded. Sent is how many of the Size bytes of the image had been read for the last of Attempts uploads;
+// the firmware cannot resume an upload, so the flash has to be started over (see WithFlashRetries).
+type FlashUploadError struct {
+	Node     int
+	Attempts int
+	Sent     int64
+	Size     int64
+	Err      error
+}
+
+func (e *FlashUploadError) Error() string {
+	return fmt.Sprintf("upload of image to node %d failed after %d of %d bytes (%d attempts): %v", e.Node, e.Sent, e.Size, e.Attempts, e.Err)
+}
+
+func (e *FlashUploadError) Unwrap() error {
+	return e.Err
+}
+
+// SDCardUsageError is returned by CheckSDCardUsage when the BMC's microSD card is fuller than the threshold.
+type SDCardUsageError struct {
+	UsagePercent     float64
+	ThresholdPercent float64
+}
+
+func (e *SDCardUsageError) Error() string {
+	return fmt.Sprintf("BMC SD card is %.1f%% full, above the %.1f%% threshold", e.UsagePercent, e.ThresholdPercent)
+}
+
+// ShutdownError is returned by ShutdownCluster when some nodes did not confirm a graceful shutdown in time.
+// Those nodes were powered off regardless.
+type ShutdownError struct {
+	Nodes []int
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("nodes %v did not shut down gracefully and were powered off", e.Nodes)
+}
+
+// BatchError is returned by Batch.Run for the step that failed. Step is its index in the batch, starting at 0.
+type BatchError struct {
+	Step int
+	Name string
+	Err  error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch step %d (%s): %v", e.Step, e.Name, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,101 @@
+package bmcapi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithFallbackURLs sets base URLs to try, in order, when the BMC cannot be reached at the current one,
+// e.g. a static IP next to turingpi.local for flaky mDNS. Only connection-level failures (DNS lookup or
+// connection refused, unreachable or timed out) fail over, so a request is never sent twice; HTTP error
+// responses are returned as they are. The first URL that answers is used for all later requests.
+func WithFallbackURLs(urls ...string) Option {
+	return func(b *BMCAPI) error {
+		fallbacks := make([]string, 0, len(urls))
+		for _, u := range urls {
+			normalized, err := normalizeBaseURL(u)
+			if err != nil {
+				return fmt.Errorf("invalid fallback URL: %w", err)
+			}
+			fallbacks = append(fallbacks, normalized)
+		}
+		b.fallbackURLs = fallbacks
+		return nil
+	}
+}
+
+// isConnectionError reports whether err means the request never reached the BMC.
+func isConnectionError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// failover retries req against the other known base URLs after it failed with firstErr,
+// and makes the first one that answers the base URL for later requests.
+func (b *BMCAPI) failover(req *http.Request, firstErr error) (*http.Response, error) {
+	b.mu.RLock()
+	current := b.BaseURL
+	candidates := append([]string{b.primaryURL}, b.fallbackURLs...)
+	b.mu.RUnlock()
+
+	endpoint, ok := strings.CutPrefix(req.URL.String(), current)
+	if !ok {
+		return nil, firstErr
+	}
+
+	for _, candidate := range candidates {
+		if candidate == current {
+			continue
+		}
+
+		retry, err := cloneRequestTo(req, candidate+endpoint)
+		if err != nil {
+			return nil, firstErr
+		}
+
+		resp, err := b.logRequest(retry)
+		if err != nil && isConnectionError(err) {
+			continue
+		}
+		if err == nil {
+			b.mu.Lock()
+			b.BaseURL = candidate
+			b.mu.Unlock()
+		}
+		return resp, err
+	}
+
+	return nil, firstErr
+}
+
+// cloneRequestTo returns a copy of req sent to rawURL, with a fresh copy of its body.
+func cloneRequestTo(req *http.Request, rawURL string) (*http.Request, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	retry.URL = u
+	retry.Host = ""
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("request body cannot be replayed")
+		}
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return retry, nil
+}
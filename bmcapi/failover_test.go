@@ -0,0 +1,63 @@
+package bmcapi
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestWithFallbackURLs(t *testing.T) {
+	var hosts []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		switch req.URL.Host {
+		case "mock", "dead.local":
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+	if err := WithFallbackURLs("http://dead.local", "https://10.0.0.2/")(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := bmc.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"mock", "dead.local", "10.0.0.2"}; !slices.Equal(hosts, want) {
+		t.Errorf("hosts tried = %v, want %v", hosts, want)
+	}
+
+	// The working fallback is sticky
+	hosts = nil
+	if _, err := bmc.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"10.0.0.2"}; !slices.Equal(hosts, want) {
+		t.Errorf("hosts tried = %v, want %v", hosts, want)
+	}
+}
+
+func TestWithFallbackURLs_NoFailoverOnHTTPError(t *testing.T) {
+	var hosts []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		return mockResponse(http.StatusUnauthorized, ""), nil
+	}))
+	if err := WithFallbackURLs("https://10.0.0.2")(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var httpErr *HTTPError
+	if _, err := bmc.GetPower(); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GetPower() error = %v, want 401 HTTPError", err)
+	}
+	if len(hosts) != 1 {
+		t.Errorf("hosts tried = %v, want only the primary", hosts)
+	}
+
+	if err := WithFallbackURLs("10.0.0.2")(bmc); err == nil {
+		t.Errorf("expected error for a fallback URL without scheme")
+	}
+}
@@ -0,0 +1,552 @@
+package bmcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bmcFlashAPIResponse is a struct that represents the response from the BMC API when a flash is requested.
+// It expects the response to be in the format {"response":[{"handle":<id>}]}, older firmware answers {"handle":<id>}.
+type bmcFlashAPIResponse struct {
+	Handle   *uint64 `json:"handle"`
+	Response []struct {
+		Handle *uint64 `json:"handle"`
+	} `json:"response"`
+}
+
+// FlashNode writes an OS image to the storage of the specified node (0-3), see FlashNodeResult.
+//
+// Deprecated: Use FlashNodeResult, which returns a SetResult.
+func (b *BMCAPI) FlashNode(ctx context.Context, node int, filename string, image io.Reader, size int64) (*string, error) {
+	return resultString(b.FlashNodeResult(ctx, node, filename, image, size))
+}
+
+// FlashNodeResult writes an OS image to the storage of the specified node (0-3).
+// The filename is only informational; size must be the exact length of image in bytes.
+//
+// Flashing is a two step process: the BMC is asked to prepare a flash, which returns an upload handle,
+// then the image is streamed to it as a multipart upload. Canceling ctx aborts the upload promptly and
+// FlashNodeResult returns ctx.Err(). FlashNodeResult honors dry-run mode without reading image.
+//
+// The firmware has no ranged or chunked uploads, so an upload that was cut off cannot be resumed. If the
+// connection fails during the upload, a *FlashUploadError reports how far it got; with WithFlashRetries and an
+// image that is an io.Seeker, such as an *os.File, the flash is instead started over from the beginning of image.
+func (b *BMCAPI) FlashNodeResult(ctx context.Context, node int, filename string, image io.Reader, size int64) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+	if size <= 0 {
+		return SetResult{}, fmt.Errorf("image size must be positive")
+	}
+
+	endpoint := "/api/bmc?opt=set&type=flash&file=" + url.QueryEscape(filename) + "&length=" + strconv.FormatInt(size, 10) + "&node=" + Node(node).queryValue()
+
+	if b.dryRun != nil {
+		b.dryRun.LogAttrs(ctx, slog.LevelInfo, "dry run: flash not started",
+			slog.String("url", b.endpointURL(endpoint)),
+			slog.Int64("size", size),
+		)
+		return SetResult{Raw: "ok"}, nil
+	}
+
+	// Retrying needs the image from the start again, so remember where it starts
+	var start int64
+	retries := 0
+	seeker, ok := image.(io.Seeker)
+	if ok && b.flashRetries > 0 {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			start, retries = offset, b.flashRetries
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		bodyBytes, err := b.bmcAPICallContext(ctx, endpoint)
+		if err != nil {
+			return SetResult{}, fmt.Errorf("error during Flash Node call: %w", err)
+		}
+
+		handle, err := flashHandleParse(bodyBytes)
+		if err != nil {
+			return SetResult{}, err
+		}
+
+		counter := &countingReader{r: image}
+		result, err := b.uploadImage(ctx, handle, filename, counter)
+
+		var urlErr *url.Error
+		if err == nil || !errors.As(err, &urlErr) {
+			return result, err
+		}
+		uploadErr := &FlashUploadError{Node: node, Attempts: attempt, Sent: counter.n, Size: size, Err: err}
+		if attempt > retries {
+			return SetResult{}, uploadErr
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return SetResult{}, errors.Join(uploadErr, fmt.Errorf("error rewinding image: %w", err))
+		}
+		if b.logger != nil {
+			b.logger.LogAttrs(ctx, slog.LevelWarn, "bmc flash upload failed, starting over",
+				slog.Int("node", node),
+				slog.Int("attempt", attempt),
+				slog.Int64("sent", counter.n),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// WithFlashRetries makes FlashNodeResult start a flash over up to retries times when the connection to the BMC
+// fails during the upload, e.g. on a flaky link. The firmware cannot resume an upload, so each retry prepares
+// a new flash and uploads the whole image again, which is only possible for images that are an io.Seeker;
+// FlashNodeWithChecksumResult, which hashes the image as it is read, is never retried. A BMC that rejects the
+// upload, or a canceled context, is not retried.
+func WithFlashRetries(retries int) Option {
+	return func(b *BMCAPI) error {
+		if retries < 0 {
+			return fmt.Errorf("flash retries must not be negative")
+		}
+		b.flashRetries = retries
+		return nil
+	}
+}
+
+// FlashNodeWithChecksum writes an OS image to the specified node (0-3) and returns the checksum of the
+// uploaded bytes, see FlashNodeWithChecksumResult.
+//
+// Deprecated: Use FlashNodeWithChecksumResult, which returns a SetResult.
+func (b *BMCAPI) FlashNodeWithChecksum(ctx context.Context, node int, filename string, image io.Reader, size int64) (*string, string, error) {
+	result, checksum, err := b.FlashNodeWithChecksumResult(ctx, node, filename, image, size)
+	raw, err := resultString(result, err)
+	return raw, checksum, err
+}
+
+// FlashNodeWithChecksumResult is like FlashNodeResult, but also returns the hex encoded SHA-256 of the image
+// bytes that were uploaded, computed while streaming.
+//
+// The firmware cannot read back or verify a flashed image, so this is the closest the SDK can get
+// to a post-flash check: compare the returned checksum with the one published for the image to be
+// sure the right, complete image was sent. In dry-run mode the image is not read and the checksum is empty.
+func (b *BMCAPI) FlashNodeWithChecksumResult(ctx context.Context, node int, filename string, image io.Reader, size int64) (SetResult, string, error) {
+	hash := sha256.New()
+	counter := &countingReader{r: io.TeeReader(image, hash)}
+
+	result, err := b.FlashNodeResult(ctx, node, filename, counter, size)
+	if err != nil {
+		return SetResult{}, "", err
+	}
+	if b.dryRun != nil {
+		return result, "", nil
+	}
+	if counter.n != size {
+		return SetResult{}, "", fmt.Errorf("uploaded %d bytes, image size is %d", counter.n, size)
+	}
+
+	return result, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// FlashNodeFromFile flashes the image file at path to the specified node (0-3), see FlashNodeFromFileResult.
+//
+// Deprecated: Use FlashNodeFromFileResult, which returns a SetResult.
+func (b *BMCAPI) FlashNodeFromFile(node int, path string) (*string, error) {
+	return resultString(b.FlashNodeFromFileResult(node, path))
+}
+
+// FlashNodeFromFileResult flashes the image file at path to the specified node (0-3), using the file's
+// base name as the filename. It cannot be canceled; open the file and use FlashNodeResult for that.
+func (b *BMCAPI) FlashNodeFromFileResult(node int, path string) (SetResult, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return SetResult{}, fmt.Errorf("image file %s does not exist", path)
+	}
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error opening image file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error reading image file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return SetResult{}, fmt.Errorf("image %s is not a regular file", path)
+	}
+
+	return b.FlashNodeResult(context.Background(), node, filepath.Base(path), file, info.Size())
+}
+
+// FlashNodes writes the same OS image to each of the given nodes (0-3), like FlashNodeResult. imageFactory
+// is called once per node for a fresh reader of the image and its size; a reader that is an io.Closer is
+// closed after its upload. Up to concurrency nodes are flashed at a time, one at a time if it is less than 2;
+// the stock firmware runs a single flash at a time, so only use concurrency with firmware that can run more.
+//
+// All nodes are validated before any is flashed. A failed node does not stop the others. The returned map has
+// an entry for every node, nil for a node flashed successfully, and the error joins the errors of all failed nodes.
+func (b *BMCAPI) FlashNodes(ctx context.Context, nodes []int, filename string, imageFactory func() (io.Reader, int64, error), concurrency int) (map[int]error, error) {
+	seen := make(map[int]bool, len(nodes))
+	for _, node := range nodes {
+		// Validate node number
+		if !Node(node).Valid() {
+			return nil, fmt.Errorf("invalid node %d: %w", node, ErrInvalidNode)
+		}
+		if seen[node] {
+			return nil, fmt.Errorf("node %d appears more than once", node)
+		}
+		seen[node] = true
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[int]error, len(nodes))
+		slots   = make(chan struct{}, max(concurrency, 1))
+	)
+	for _, node := range nodes {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			err := b.flashFromFactory(ctx, node, filename, imageFactory)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[node] = err
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, node := range nodes {
+		if err := results[node]; err != nil {
+			errs = append(errs, fmt.Errorf("node %d: %w", node, err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// flashFromFactory is a helper function for FlashNodes that flashes node with a fresh image from imageFactory.
+func (b *BMCAPI) flashFromFactory(ctx context.Context, node int, filename string, imageFactory func() (io.Reader, int64, error)) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	image, size, err := imageFactory()
+	if err != nil {
+		return fmt.Errorf("error opening image: %w", err)
+	}
+	if closer, ok := image.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	_, err = b.FlashNodeResult(ctx, node, filename, image, size)
+	return err
+
+}
+
+// FlashStatus is the progress of the flash operation the BMC is running.
+// Phase is the firmware's name for the current step in lower case, e.g. "setup", "transferring",
+// "done" or "error", and "idle" when no flash was started since the BMC booted.
+// Percent is only set while the image is being transferred.
+type FlashStatus struct {
+	Phase        string
+	Done         bool
+	Percent      *float64
+	BytesWritten int64
+	Size         int64
+	Error        string
+}
+
+// bmcFlashProgress is the progress object the firmware reports while an image is being transferred.
+type bmcFlashProgress struct {
+	Size         int64 `json:"size"`
+	BytesWritten int64 `json:"bytes_written"`
+}
+
+// FlashStatus returns the progress of the flash started on the specified node (0-3) with FlashNode.
+// The BMC runs a single flash at a time and reports it regardless of node. Firmware without
+// flash progress reporting returns ErrUnsupported.
+func (b *BMCAPI) FlashStatus(node int) (FlashStatus, error) {
+	return b.flashStatus(context.Background(), node)
+}
+
+// flashStatus is FlashStatus with the request made under ctx.
+func (b *BMCAPI) flashStatus(ctx context.Context, node int) (FlashStatus, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return FlashStatus{}, ErrInvalidNode
+	}
+
+	bodyBytes, err := b.capabilityAPICallContext(ctx, "flash status", "/api/bmc?opt=get&type=flash&node="+Node(node).queryValue())
+	if err != nil {
+		return FlashStatus{}, fmt.Errorf("error during Flash Status call: %w", err)
+	}
+
+	return flashStatusParse(bodyBytes)
+}
+
+// WaitForFlash polls FlashStatus for the specified node (0-3) until the flash is done, and returns an
+// error if the flash failed, no flash is running, or ctx expires first.
+func (b *BMCAPI) WaitForFlash(ctx context.Context, node int) error {
+	return b.pollUntil(ctx, defaultPollInterval, func() (bool, error) {
+		status, err := b.flashStatus(ctx, node)
+		if err != nil {
+			return false, err
+		}
+		switch status.Phase {
+		case "error":
+			return false, fmt.Errorf("flash of node %d failed: %s", node, status.Error)
+		case "idle":
+			return false, fmt.Errorf("no flash is running on node %d", node)
+		}
+		return status.Done, nil
+	})
+}
+
+// FlashLog returns the BMC's log of the last flash operation on the specified node (0-3) as text,
+// e.g. to find out why a flash started with FlashNode failed. Firmware that does not keep a flash log
+// returns ErrUnsupported.
+func (b *BMCAPI) FlashLog(node int) (string, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return "", ErrInvalidNode
+	}
+
+	bodyBytes, err := b.capabilityAPICall("flash log", "/api/bmc?opt=get&type=flash_log&node="+Node(node).queryValue())
+	if err != nil {
+		return "", fmt.Errorf("error during Flash Log call: %w", err)
+	}
+
+	return flashLogParse(bodyBytes)
+}
+
+// flashLogParse is a helper function that extracts the log text from a flash log response in the format
+// {"response":[{"log":<log>}]} or {"response":[{"result":<log>}]}, where the log is a string or an array of lines.
+func flashLogParse(bodyBytes []byte) (string, error) {
+
+	var parsed struct {
+		Response []map[string]json.RawMessage `json:"response"`
+	}
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return "", fmt.Errorf("error parsing json in flash log response: %w", err)
+	}
+	if len(parsed.Response) == 0 {
+		return "", fmt.Errorf("no data in response")
+	}
+
+	raw, ok := parsed.Response[0]["log"]
+	if !ok {
+		raw, ok = parsed.Response[0]["result"]
+	}
+	if !ok || string(raw) == "null" {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err != nil {
+		return "", fmt.Errorf("unexpected flash log %s", raw)
+	}
+
+	return strings.Join(lines, "\n"), nil
+
+}
+
+// flashStatusParse is a helper function that parses a flash status response. The firmware reports the phase either
+// as a bare string such as "Setup" or as an object keyed by the phase, e.g. {"Transferring":{...}} or {"Error":"<message>"},
+// optionally wrapped in {"response":[{"result":<status>}]}.
+func flashStatusParse(bodyBytes []byte) (FlashStatus, error) {
+
+	var raw json.RawMessage
+	if err := unmarshalResponse(bodyBytes, &raw); err != nil {
+		return FlashStatus{}, fmt.Errorf("error parsing json in flash status response: %w", err)
+	}
+
+	var wrapped struct {
+		Response []struct {
+			Result json.RawMessage `json:"result"`
+		} `json:"response"`
+	}
+	if json.Unmarshal(raw, &wrapped) == nil && len(wrapped.Response) > 0 {
+		raw = wrapped.Response[0].Result
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" || string(trimmed) == `""` || string(trimmed) == "{}" {
+		return FlashStatus{Phase: "idle"}, nil
+	}
+
+	var phase string
+	if err := json.Unmarshal(trimmed, &phase); err == nil {
+		phase = strings.ToLower(phase)
+		return FlashStatus{Phase: phase, Done: phase == "done"}, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &object); err != nil || len(object) != 1 {
+		return FlashStatus{}, fmt.Errorf("unexpected flash status %s", trimmed)
+	}
+
+	var status FlashStatus
+	for key, value := range object {
+		status.Phase = strings.ToLower(key)
+		switch status.Phase {
+		case "done":
+			status.Done = true
+		case "error":
+			if err := json.Unmarshal(value, &status.Error); err != nil {
+				status.Error = string(value)
+			}
+		case "transferring":
+			var progress bmcFlashProgress
+			if err := json.Unmarshal(value, &progress); err != nil {
+				return FlashStatus{}, fmt.Errorf("error parsing json in flash status response: %w", err)
+			}
+			status.BytesWritten = progress.BytesWritten
+			status.Size = progress.Size
+			if progress.Size > 0 {
+				percent := float64(progress.BytesWritten) * 100 / float64(progress.Size)
+				status.Percent = &percent
+			}
+		}
+	}
+
+	return status, nil
+
+}
+
+// flashHandleParse is a helper function that extracts the upload handle from a flash request response.
+func flashHandleParse(bodyBytes []byte) (uint64, error) {
+
+	var parsed bmcFlashAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return 0, fmt.Errorf("error parsing json in flash response: %w", err)
+	}
+	if len(parsed.Response) > 0 && parsed.Response[0].Handle != nil {
+		return *parsed.Response[0].Handle, nil
+	}
+	if parsed.Handle != nil {
+		return *parsed.Handle, nil
+	}
+
+	return 0, fmt.Errorf("flash response does not contain an upload handle")
+
+}
+
+// uploadImage streams image to the upload endpoint for handle as a multipart form.
+func (b *BMCAPI) uploadImage(ctx context.Context, handle uint64, filename string, image io.Reader) (SetResult, error) {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, &contextReader{ctx: ctx, r: image})
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.endpointURL("/api/bmc/upload/"+strconv.FormatUint(handle, 10)), pr)
+	if err != nil {
+		pr.Close()
+		<-written
+		return SetResult{}, fmt.Errorf("Error creating upload request: %w", err)
+	}
+	b.setAuthHeaders(req)
+	b.setIdempotencyKey(req)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := b.doRequest(req)
+	// Unblock the writer goroutine if the request ended before the whole body was sent, and wait until it stopped reading image
+	pr.Close()
+	<-written
+	if ctx.Err() != nil {
+		if err == nil {
+			resp.Body.Close()
+		}
+		return SetResult{}, ctx.Err()
+	}
+	if err != nil {
+		return SetResult{}, fmt.Errorf("Error uploading image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return SetResult{}, httpError(resp, bodyBytes)
+	}
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	// Firmware versions differ in whether the upload answers with a JSON result or plain text; an empty body is success.
+	// Anything other than "ok" is the firmware rejecting the upload and is reported as an *APIError like resultAPIParse does.
+	var parsed bmcResultAPIResponse
+	result := string(bytes.TrimSpace(bodyBytes))
+	if err := unmarshalResponse(bodyBytes, &parsed); err == nil && len(parsed.Response) > 0 && parsed.Response[0].Result != "" {
+		result = parsed.Response[0].Result
+	}
+	if result == "" {
+		result = "ok"
+	}
+	if result != "ok" {
+		return SetResult{}, &APIError{Result: result}
+	}
+
+	return SetResult{Raw: result}, nil
+}
+
+// countingReader is an io.Reader that counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// contextReader is an io.Reader that fails with the context's error once it is done,
+// so a canceled upload stops reading the image immediately.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,382 @@
+package bmcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// endlessImage is an image reader that never runs out and counts the bytes read from it.
+type endlessImage struct {
+	read atomic.Int64
+}
+
+func (e *endlessImage) Read(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	e.read.Add(int64(len(p)))
+	return len(p), nil
+}
+
+func flashTransport(t *testing.T, upload func(req *http.Request) (*http.Response, error)) mockTransport {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Method == "POST" {
+			if req.URL.Path != "/api/bmc/upload/42" {
+				t.Errorf("upload sent to %s", req.URL.Path)
+			}
+			return upload(req)
+		}
+		query := req.URL.Query()
+		if query.Get("type") != "flash" || query.Get("node") != "1" || query.Get("file") != "rk1.img" {
+			t.Errorf("unexpected flash request: %s", req.URL)
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"handle":42}]}`), nil
+	}
+}
+
+func TestBMCAPI_FlashNode(t *testing.T) {
+	image := "not really an image"
+	bmc := newMockBMCAPI(flashTransport(t, func(req *http.Request) (*http.Response, error) {
+		_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("invalid content type: %v", err)
+		}
+		part, err := multipart.NewReader(req.Body, params["boundary"]).NextPart()
+		if err != nil {
+			t.Fatalf("invalid multipart body: %v", err)
+		}
+		got, _ := io.ReadAll(part)
+		if string(got) != image || part.FileName() != "rk1.img" {
+			t.Errorf("uploaded %q as %q", got, part.FileName())
+		}
+		return mockResponse(http.StatusOK, ""), nil
+	}))
+
+	result, err := bmc.FlashNode(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != "ok" {
+		t.Errorf("FlashNode() = %q, want %q", *result, "ok")
+	}
+}
+
+func TestBMCAPI_FlashNode_Rejected(t *testing.T) {
+	for _, body := range []string{`{"response":[{"result":"image too large"}]}`, "image too large\n"} {
+		bmc := newMockBMCAPI(flashTransport(t, func(req *http.Request) (*http.Response, error) {
+			io.Copy(io.Discard, req.Body)
+			return mockResponse(http.StatusOK, body), nil
+		}))
+
+		image := "not really an image"
+		result, err := bmc.FlashNodeResult(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image)))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Result != "image too large" {
+			t.Errorf("FlashNodeResult() with response %q = %+v, %v, want an APIError", body, result, err)
+		}
+	}
+
+	bmc := newMockBMCAPI(flashTransport(t, func(req *http.Request) (*http.Response, error) {
+		io.Copy(io.Discard, req.Body)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+	if result, err := bmc.FlashNodeResult(context.Background(), 1, "rk1.img", strings.NewReader("image"), 5); err != nil || !result.Ok() {
+		t.Errorf("FlashNodeResult() = %+v, %v, want an ok result", result, err)
+	}
+}
+
+func TestBMCAPI_FlashNode_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	image := &endlessImage{}
+	bmc := newMockBMCAPI(flashTransport(t, func(req *http.Request) (*http.Response, error) {
+		buf := make([]byte, 32*1024)
+		for total := 0; ; {
+			n, err := req.Body.Read(buf)
+			total += n
+			if total > 1<<20 {
+				cancel()
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := bmc.FlashNode(ctx, 1, "rk1.img", image, 10<<30)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("FlashNode() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("FlashNode did not return after cancel")
+	}
+
+	// The image must not be read any further once the upload was canceled
+	readAtCancel := image.read.Load()
+	time.Sleep(20 * time.Millisecond)
+	if read := image.read.Load(); read != readAtCancel {
+		t.Errorf("image was still read after cancel: %d bytes, then %d", readAtCancel, read)
+	}
+}
+
+func TestBMCAPI_FlashNode_Retry(t *testing.T) {
+	image := strings.Repeat("image data ", 1000)
+	var uploads []string
+	bmc := newMockBMCAPI(flashTransport(t, func(req *http.Request) (*http.Response, error) {
+		body := make([]byte, 100)
+		if len(uploads) == 0 {
+			// The link drops part way through the first upload
+			io.ReadFull(req.Body, body)
+			uploads = append(uploads, string(body))
+			return nil, io.ErrUnexpectedEOF
+		}
+		all, _ := io.ReadAll(req.Body)
+		uploads = append(uploads, string(all))
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	_, err := bmc.FlashNodeResult(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image)))
+	var uploadErr *FlashUploadError
+	if !errors.As(err, &uploadErr) || uploadErr.Attempts != 1 || uploadErr.Size != int64(len(image)) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("FlashNodeResult() error = %v, want a FlashUploadError after one attempt", err)
+	}
+
+	uploads = nil
+	if err := WithFlashRetries(2)(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.FlashNodeResult(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(uploads) != 2 || !strings.Contains(uploads[1], image) {
+		t.Errorf("made %d uploads, want the whole image uploaded again on the second", len(uploads))
+	}
+
+	// An image that cannot be rewound is not retried
+	uploads = nil
+	_, err = bmc.FlashNodeResult(context.Background(), 1, "rk1.img", io.LimitReader(strings.NewReader(image), int64(len(image))), int64(len(image)))
+	if !errors.As(err, &uploadErr) || len(uploads) != 1 {
+		t.Errorf("FlashNodeResult() with a plain reader error = %v after %d uploads, want a FlashUploadError after one", err, len(uploads))
+	}
+
+	if err := WithFlashRetries(-1)(bmc); err == nil {
+		t.Errorf("expected error for negative retries")
+	}
+}
+
+func TestBMCAPI_FlashNodeFromFileResult(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rk1.img")
+	if err := os.WriteFile(path, []byte("image data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var uploaded string
+	flash := flashTransport(t, func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		uploaded = string(body)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Method == "GET" && req.URL.Query().Get("length") != "10" {
+			t.Errorf("flash length = %q, want 10", req.URL.Query().Get("length"))
+		}
+		return flash(req)
+	}))
+
+	if result, err := bmc.FlashNodeFromFileResult(1, path); err != nil || !result.Ok() {
+		t.Fatalf("FlashNodeFromFileResult() = %+v, %v, want an ok result", result, err)
+	}
+	if !strings.Contains(uploaded, "image data") {
+		t.Errorf("image was not uploaded")
+	}
+
+	_, err := bmc.FlashNodeFromFileResult(1, filepath.Join(t.TempDir(), "missing.img"))
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("FlashNodeFromFileResult() error = %v, want a does not exist error", err)
+	}
+
+	// The deprecated method returns the same result as a *string
+	if raw, err := bmc.FlashNodeFromFile(1, path); err != nil || *raw != "ok" {
+		t.Errorf("FlashNodeFromFile() = %v, %v", raw, err)
+	}
+}
+
+func TestBMCAPI_FlashNodeWithChecksumResult(t *testing.T) {
+	image := "not really an image"
+	bmc := newMockBMCAPI(flashTransport(t, func(req *http.Request) (*http.Response, error) {
+		io.Copy(io.Discard, req.Body)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	result, checksum, err := bmc.FlashNodeWithChecksumResult(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Ok() {
+		t.Errorf("FlashNodeWithChecksumResult() = %+v, want an ok result", result)
+	}
+	sum := sha256.Sum256([]byte(image))
+	if want := hex.EncodeToString(sum[:]); checksum != want {
+		t.Errorf("checksum = %s, want %s", checksum, want)
+	}
+
+	// A short image must not be reported as flashed with a checksum of a partial upload
+	if _, _, err := bmc.FlashNodeWithChecksumResult(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image))+1); err == nil {
+		t.Errorf("expected error for an image shorter than size")
+	}
+
+	// The deprecated method returns the same result as a *string
+	raw, deprecatedChecksum, err := bmc.FlashNodeWithChecksum(context.Background(), 1, "rk1.img", strings.NewReader(image), int64(len(image)))
+	if err != nil || *raw != "ok" || deprecatedChecksum != checksum {
+		t.Errorf("FlashNodeWithChecksum() = %v, %q, %v", raw, deprecatedChecksum, err)
+	}
+}
+
+func TestFlashStatusParse(t *testing.T) {
+	half := 50.0
+	tests := []struct {
+		body string
+		want FlashStatus
+	}{
+		{`"Setup"`, FlashStatus{Phase: "setup"}},
+		{`{"Transferring":{"id":7,"process_name":"rk1.img","size":200,"cancel":{},"bytes_written":100}}`, FlashStatus{Phase: "transferring", Percent: &half, BytesWritten: 100, Size: 200}},
+		{`{"Done":[{"secs":12,"nanos":0},200]}`, FlashStatus{Phase: "done", Done: true}},
+		{`{"Error":"checksum mismatch"}`, FlashStatus{Phase: "error", Error: "checksum mismatch"}},
+		{`{"response":[{"result":{"Done":[{"secs":12,"nanos":0},200]}}]}`, FlashStatus{Phase: "done", Done: true}},
+		{`null`, FlashStatus{Phase: "idle"}},
+	}
+	for _, tt := range tests {
+		got, err := flashStatusParse([]byte(tt.body))
+		if err != nil {
+			t.Errorf("flashStatusParse(%s) error: %v", tt.body, err)
+			continue
+		}
+		if got.Phase != tt.want.Phase || got.Done != tt.want.Done || got.Error != tt.want.Error ||
+			got.BytesWritten != tt.want.BytesWritten || got.Size != tt.want.Size ||
+			(got.Percent == nil) != (tt.want.Percent == nil) || (got.Percent != nil && *got.Percent != *tt.want.Percent) {
+			t.Errorf("flashStatusParse(%s) = %+v, want %+v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestBMCAPI_WaitForFlash(t *testing.T) {
+	statuses := []string{
+		`{"Transferring":{"size":200,"bytes_written":100}}`,
+		`{"Error":"write failed"}`,
+	}
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("opt") != "get" || req.URL.Query().Get("type") != "flash" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		body := statuses[0]
+		if len(statuses) > 1 {
+			statuses = statuses[1:]
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := bmc.WaitForFlash(ctx, 1)
+	if err == nil || !strings.Contains(err.Error(), "write failed") {
+		t.Errorf("WaitForFlash() error = %v, want the firmware's flash error", err)
+	}
+}
+
+func TestBMCAPI_FlashLog(t *testing.T) {
+	body := `{"response":[{"log":"writing image\nverifying\nchecksum mismatch"}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "flash_log" || req.URL.Query().Get("node") != "2" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	want := "writing image\nverifying\nchecksum mismatch"
+	if got, err := bmc.FlashLog(2); err != nil || got != want {
+		t.Errorf("FlashLog() = %q, %v, want %q", got, err, want)
+	}
+
+	body = `{"response":[{"result":["writing image","verifying","checksum mismatch"]}]}`
+	if got, err := bmc.FlashLog(2); err != nil || got != want {
+		t.Errorf("FlashLog() with lines = %q, %v, want %q", got, err, want)
+	}
+
+	if _, err := bmc.FlashLog(1); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("FlashLog() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := bmc.FlashLog(4); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("FlashLog(4) error = %v, want ErrInvalidNode", err)
+	}
+}
+
+func TestBMCAPI_FlashNodes(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		uploaded = map[string]int{}
+	)
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if req.Method == "POST" {
+			body, _ := io.ReadAll(req.Body)
+			if strings.Contains(string(body), "image data") {
+				uploaded[req.URL.Path]++
+			}
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		}
+		// Node 2 fails to prepare the flash
+		node := req.URL.Query().Get("node")
+		if node == "2" {
+			return mockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"handle":`+node+`}]}`), nil
+	}))
+
+	var opened atomic.Int32
+	factory := func() (io.Reader, int64, error) {
+		opened.Add(1)
+		return strings.NewReader("image data"), 10, nil
+	}
+
+	results, err := bmc.FlashNodes(context.Background(), []int{0, 1, 2, 3}, "rk1.img", factory, 2)
+	if err == nil || !strings.Contains(err.Error(), "node 2") {
+		t.Errorf("FlashNodes() error = %v, want a failure of node 2", err)
+	}
+	if len(results) != 4 || results[0] != nil || results[1] != nil || results[2] == nil || results[3] != nil {
+		t.Errorf("FlashNodes() results = %v", results)
+	}
+	if opened.Load() != 4 {
+		t.Errorf("image was opened %d times, want once per node", opened.Load())
+	}
+	for _, path := range []string{"/api/bmc/upload/0", "/api/bmc/upload/1", "/api/bmc/upload/3"} {
+		if uploaded[path] != 1 {
+			t.Errorf("uploads = %v, want one to %s", uploaded, path)
+		}
+	}
+
+	opened.Store(0)
+	if _, err := bmc.FlashNodes(context.Background(), []int{0, 4}, "rk1.img", factory, 1); !errors.Is(err, ErrInvalidNode) || opened.Load() != 0 {
+		t.Errorf("FlashNodes() with an invalid node = %v, want ErrInvalidNode before any flash", err)
+	}
+}
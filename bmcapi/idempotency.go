@@ -0,0 +1,35 @@
+package bmcapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// idempotencyHeader is the header WithIdempotencyKeys sets on write requests.
+const idempotencyHeader = "Idempotency-Key"
+
+// WithIdempotencyKeys sends an Idempotency-Key header with a new random key on every write request, such as
+// a power change or a flash upload. When the SDK sends a request again, after failing over to a fallback URL,
+// re-establishing a rejected session or following a redirect, the key stays the same, so a proxy or firmware
+// that honors the header can drop duplicates of one call. The stock firmware ignores the header.
+//
+// The SDK itself never sends a write twice where the first attempt may have been applied: failover only
+// happens when the BMC could not be reached, and a request is only repeated after the BMC rejected its session.
+func WithIdempotencyKeys() Option {
+	return func(b *BMCAPI) error {
+		b.idempotencyKeys = true
+		return nil
+	}
+}
+
+// setIdempotencyKey sets a new idempotency key on req if b sends them.
+func (b *BMCAPI) setIdempotencyKey(req *http.Request) {
+	if !b.idempotencyKeys {
+		return
+	}
+
+	key := make([]byte, 16)
+	rand.Read(key)
+	req.Header.Set(idempotencyHeader, hex.EncodeToString(key))
+}
@@ -0,0 +1,52 @@
+package bmcapi
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestWithIdempotencyKeys(t *testing.T) {
+	var keys []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.Header.Get(idempotencyHeader))
+		if req.URL.Host == "mock" {
+			return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+		}
+		if req.URL.Query().Get("opt") == "get" {
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+	for _, opt := range []Option{WithIdempotencyKeys(), WithFallbackURLs("http://10.0.0.2")} {
+		if err := opt(bmc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The request is sent again to the fallback URL with the same key
+	if _, err := bmc.SetPowerResult(0, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Fatalf("keys = %q, want the same key on both attempts", keys)
+	}
+
+	first := keys[0]
+	keys = nil
+	if _, err := bmc.SetPowerResult(0, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0] == "" || keys[0] == first {
+		t.Errorf("keys = %q, want a new key for a new call", keys)
+	}
+
+	keys = nil
+	if _, err := bmc.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "" {
+		t.Errorf("read request was sent with key %q", keys)
+	}
+}
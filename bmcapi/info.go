@@ -0,0 +1,188 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// infoEndpoint is the endpoint behind Info. Basic auth also uses it to test credentials.
+const infoEndpoint = "/api/bmc?opt=get&type=info"
+
+// Info describes the BMC's network interfaces and storage devices.
+// Unlike Other, which reports the firmware version and build details with a single IP and MAC,
+// Info lists every network interface and the capacity of the BMC's storage.
+type Info struct {
+	IP      []InfoInterface `json:"ip"`
+	Storage []InfoStorage   `json:"storage"`
+}
+
+// InfoInterface is a network interface of the BMC.
+type InfoInterface struct {
+	Device string `json:"device"`
+	IP     string `json:"ip"`
+	MAC    string `json:"mac"`
+}
+
+// InfoStorage is a storage device of the BMC, such as its internal flash or the microSD card.
+type InfoStorage struct {
+	Name       string `json:"name"`
+	TotalBytes uint64 `json:"total_bytes"`
+	BytesFree  uint64 `json:"bytes_free"`
+}
+
+// bmcInfoAPIResponse is a struct that represents the response from the BMC API for the info endpoint.
+// It expects the response to be in the format {"response":[{"result":[{"ip":[...],"storage":[...]}] }]}
+type bmcInfoAPIResponse struct {
+	Response []struct {
+		Result []Info `json:"result"`
+	} `json:"response"`
+}
+
+// Info returns the BMC's network interfaces and storage devices.
+func (b *BMCAPI) Info() (*Info, error) {
+	bodyBytes, err := b.bmcAPICall(infoEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error during Info call: %w", err)
+	}
+
+	var parsed bmcInfoAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing json in info response: %w", err)
+	}
+	if len(parsed.Response) == 0 || len(parsed.Response[0].Result) == 0 {
+		return nil, fmt.Errorf("no data in response")
+	}
+
+	return &parsed.Response[0].Result[0], nil
+}
+
+// NodeInfo is what the firmware reports about a node besides its power state.
+// PowerOnTime is nil while the node is off.
+type NodeInfo struct {
+	Name        string  `json:"name"`
+	ModuleName  string  `json:"module_name"`
+	PowerOnTime *uint64 `json:"power_on_time"`
+	UARTBaud    int     `json:"uart_baud"`
+}
+
+// bmcNodeInfoAPIResponse is a struct that represents the response from the BMC API for the node info endpoint.
+// It expects the response to be in the format {"response":[{"result":[{<node1>},{<node2>},{<node3>},{<node4>}] }]}
+type bmcNodeInfoAPIResponse struct {
+	Response []struct {
+		Result []NodeInfo `json:"result"`
+	} `json:"response"`
+}
+
+// NodeInfo returns information about each node (0-3), in node order.
+// Firmware before node info support returns ErrUnsupported.
+func (b *BMCAPI) NodeInfo() ([]NodeInfo, error) {
+	bodyBytes, err := b.capabilityAPICall("node info", "/api/bmc?opt=get&type=node_info")
+	if err != nil {
+		return nil, fmt.Errorf("error during Node Info call: %w", err)
+	}
+
+	var parsed bmcNodeInfoAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing json in node info response: %w", err)
+	}
+	if len(parsed.Response) == 0 || len(parsed.Response[0].Result) == 0 {
+		return nil, fmt.Errorf("no data in response")
+	}
+
+	return parsed.Response[0].Result, nil
+}
+
+// ModuleTypes returns the compute module in each slot (0-3) as the firmware names it, e.g. "RK1", "CM4"
+// or "Jetson", so tooling can pick the right image to flash. Empty slots, and slots whose module the
+// firmware could not identify, are "". It is read from NodeInfo, so older firmware returns ErrUnsupported.
+func (b *BMCAPI) ModuleTypes() ([4]string, error) {
+	var modules [4]string
+
+	nodes, err := b.NodeInfo()
+	if err != nil {
+		return modules, err
+	}
+
+	for node := range min(len(nodes), len(modules)) {
+		module := strings.TrimSpace(nodes[node].ModuleName)
+		switch strings.ToLower(module) {
+		case "unknown", "none", "empty", "n/a":
+			module = ""
+		}
+		modules[node] = module
+	}
+
+	return modules, nil
+}
+
+// HardwareInfo identifies the board and the hardware in its node slots, e.g. for asset tracking.
+// Fields the firmware does not report are "".
+type HardwareInfo struct {
+	Serial   string
+	Revision string
+	Nodes    [4]NodeHardware
+}
+
+// NodeHardware identifies the compute module in a node slot. ID is the module's hardware ID as the firmware
+// reports it, such as a SoC or board ID.
+type NodeHardware struct {
+	Serial string
+	ID     string
+}
+
+// HardwareInfo returns the serial number and revision of the board and the serial numbers and hardware IDs
+// of the nodes (0-3). Details the firmware does not report are left empty rather than failing the call.
+// Firmware that does not report hardware details at all returns ErrUnsupported.
+func (b *BMCAPI) HardwareInfo() (*HardwareInfo, error) {
+	bodyBytes, err := b.capabilityAPICall("hardware info", "/api/bmc?opt=get&type=hardware_info")
+	if err != nil {
+		return nil, fmt.Errorf("error during Hardware Info call: %w", err)
+	}
+
+	// The details are reported as {"response":[{"result":[{"serial":"<serial>","revision":"<rev>","nodes":[{"serial":"<serial>","id":"<id>"}, ...]}] }]}
+	// where older builds use board_serial and board_revision
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing hardware info response: %w", err)
+	}
+
+	info := HardwareInfo{
+		Serial:   hardwareText(result, "serial", "board_serial"),
+		Revision: hardwareText(result, "revision", "board_revision"),
+	}
+
+	var nodes []map[string]json.RawMessage
+	if raw, ok := result["nodes"]; ok && json.Unmarshal(raw, &nodes) == nil {
+		for node := range min(len(nodes), len(info.Nodes)) {
+			info.Nodes[node] = NodeHardware{
+				Serial: hardwareText(nodes[node], "serial"),
+				ID:     hardwareText(nodes[node], "id", "hardware_id"),
+			}
+		}
+	}
+
+	return &info, nil
+}
+
+// hardwareText returns the first of keys present in object as text, accepting strings and numbers.
+// Missing, null and malformed values are "".
+func hardwareText(object map[string]json.RawMessage, keys ...string) string {
+	for _, key := range keys {
+		raw, ok := object[key]
+		if !ok {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			return strings.TrimSpace(text)
+		}
+		var number json.Number
+		if err := json.Unmarshal(raw, &number); err == nil {
+			return number.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestBMCAPI_Info(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "info" {
+			return mockResponse(http.StatusNotFound, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"ip":[{"device":"eth0","ip":"192.168.1.10","mac":"02:00:00:00:00:01"}],"storage":[{"name":"BMC","total_bytes":1048576,"bytes_free":524288}]}]}]}`), nil
+	}))
+
+	got, err := bmc.Info()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &Info{
+		IP:      []InfoInterface{{Device: "eth0", IP: "192.168.1.10", MAC: "02:00:00:00:00:01"}},
+		Storage: []InfoStorage{{Name: "BMC", TotalBytes: 1048576, BytesFree: 524288}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Info() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBMCAPI_NodeInfo(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "node_info" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"name":"Node 1","module_name":"RK1","power_on_time":120,"uart_baud":115200},{"name":"Node 2","module_name":"","power_on_time":null,"uart_baud":115200}]}]}`), nil
+	}))
+
+	nodes, err := bmc.NodeInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].ModuleName != "RK1" || nodes[0].PowerOnTime == nil || *nodes[0].PowerOnTime != 120 || nodes[1].PowerOnTime != nil {
+		t.Errorf("NodeInfo() = %+v", nodes)
+	}
+}
+
+func TestBMCAPI_ModuleTypes(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"module_name":"RK1"},{"module_name":""},{"module_name":"Jetson Orin NX"},{"module_name":"Unknown"}]}]}`), nil
+	}))
+
+	got, err := bmc.ModuleTypes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := [4]string{"RK1", "", "Jetson Orin NX", ""}; got != want {
+		t.Errorf("ModuleTypes() = %q, want %q", got, want)
+	}
+}
+
+func TestBMCAPI_HardwareInfo(t *testing.T) {
+	body := `{"response":[{"result":[{"serial":"TP2-0042","board_revision":"2.5","nodes":[{"serial":"RK1-1","id":"0x3588"},{"serial":null},{"id":1234}]}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "hardware_info" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	info, err := bmc.HardwareInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := HardwareInfo{
+		Serial:   "TP2-0042",
+		Revision: "2.5",
+		Nodes:    [4]NodeHardware{{Serial: "RK1-1", ID: "0x3588"}, {}, {ID: "1234"}, {}},
+	}
+	if *info != want {
+		t.Errorf("HardwareInfo() = %+v, want %+v", *info, want)
+	}
+
+	// Missing or malformed details leave fields empty
+	body = `{"response":[{"result":[{"revision":"2.4","nodes":"none"}]}]}`
+	info, err = bmc.HardwareInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *info != (HardwareInfo{Revision: "2.4"}) {
+		t.Errorf("HardwareInfo() = %+v, want only the revision", *info)
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusNotFound, ""), nil
+	}))
+	if _, err := unsupported.HardwareInfo(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("HardwareInfo() error = %v, want ErrUnsupported", err)
+	}
+}
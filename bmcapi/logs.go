@@ -0,0 +1,65 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxLogLines is the most lines BMCLogs asks for, so a single call cannot pull the whole log off the BMC.
+const maxLogLines = 10000
+
+// bmcLogAPIResponse is a struct that represents the response from the BMC API for the log endpoint.
+// It expects the response to be in the format {"response":[{"result":"<text>"}]} or, with a line per
+// element, {"response":[{"result":["<line>", ...]}]}
+type bmcLogAPIResponse struct {
+	Response []struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"response"`
+}
+
+// BMCLogs returns the last lines (1-10000) of the BMC's own log, e.g. to see why a flash or power change failed
+// without logging in to the BMC. Lines are separated by "\n". Fewer lines are returned if the log is shorter.
+// Firmware that does not expose its log returns ErrUnsupported.
+func (b *BMCAPI) BMCLogs(lines int) (string, error) {
+	// Validate line count
+	if lines < 1 || lines > maxLogLines {
+		return "", fmt.Errorf("lines must be between 1 and %d", maxLogLines)
+	}
+
+	bodyBytes, err := b.capabilityAPICall("BMC logs", "/api/bmc?opt=get&type=log&lines="+strconv.Itoa(lines))
+	if err != nil {
+		return "", fmt.Errorf("error during BMC Logs call: %w", err)
+	}
+
+	var parsed bmcLogAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return "", fmt.Errorf("error parsing json in log response: %w (body: %s)", err, bodySnippet(bodyBytes))
+	}
+	if len(parsed.Response) == 0 {
+		return "", fmt.Errorf("no data in response")
+	}
+
+	var text string
+	if err := json.Unmarshal(parsed.Response[0].Result, &text); err != nil {
+		var logLines []string
+		if err := json.Unmarshal(parsed.Response[0].Result, &logLines); err != nil {
+			return "", fmt.Errorf("unexpected log format (body: %s)", bodySnippet(bodyBytes))
+		}
+		text = strings.Join(logLines, "\n")
+	}
+
+	return lastLines(text, lines), nil
+}
+
+// lastLines returns the last n lines of text, for firmware that ignores the requested line count.
+func lastLines(text string, n int) string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	split := strings.Split(text, "\n")
+	if len(split) > n {
+		split = split[len(split)-n:]
+	}
+	return strings.Join(split, "\n")
+}
@@ -0,0 +1,51 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_BMCLogs(t *testing.T) {
+	var requested string
+	body := `{"response":[{"result":"boot\r\nflash started\nflash failed: timeout\n"}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "log" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		requested = req.URL.Query().Get("lines")
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	logs, err := bmc.BMCLogs(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requested != "2" {
+		t.Errorf("lines = %q, want 2", requested)
+	}
+	if want := "flash started\nflash failed: timeout"; logs != want {
+		t.Errorf("BMCLogs() = %q, want %q", logs, want)
+	}
+
+	body = `{"response":[{"result":["boot","flash started"]}]}`
+	if logs, err := bmc.BMCLogs(10); err != nil || logs != "boot\nflash started" {
+		t.Errorf("BMCLogs() with a line array = %q, %v", logs, err)
+	}
+
+	body = `{"response":[{"result":{"log":1}}]}`
+	if _, err := bmc.BMCLogs(10); err == nil {
+		t.Errorf("expected error for an unexpected log format")
+	}
+
+	if _, err := bmc.BMCLogs(0); err == nil {
+		t.Errorf("expected error for zero lines")
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusNotFound, ""), nil
+	}))
+	if _, err := unsupported.BMCLogs(10); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("BMCLogs() error = %v, want ErrUnsupported", err)
+	}
+}
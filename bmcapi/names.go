@@ -0,0 +1,146 @@
+package bmcapi
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxNodeNameLength is the longest name in characters SetNodeName stores on the BMC.
+const maxNodeNameLength = 64
+
+// SetNodeNames assigns names to nodes (0-3) so they can be addressed by name, e.g. {0: "storage", 1: "worker1"}.
+// It replaces any names set before. Names must be unique and non-empty; nodes without a name can
+// still be addressed by index. The names only exist in this client, they are not stored on the BMC.
+func (b *BMCAPI) SetNodeNames(names map[int]string) error {
+	seen := make(map[string]int, len(names))
+	for node, name := range names {
+		// Validate node number
+		if !Node(node).Valid() {
+			return ErrInvalidNode
+		}
+		if name == "" {
+			return fmt.Errorf("name of node %d must not be empty", node)
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("name %q is used for both node %d and node %d", name, other, node)
+		}
+		seen[name] = node
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nodeNames = maps.Clone(names)
+
+	return nil
+}
+
+// LoadNodeNames replaces the names set with SetNodeNames by the names the firmware reports in NodeInfo, e.g. ones stored
+// with SetNodeName, so nodes can be addressed by the names shown on the board. Nodes without a name on the BMC
+// are left without one. It fails if two nodes have the same name. Older firmware returns ErrUnsupported.
+func (b *BMCAPI) LoadNodeNames() error {
+	nodes, err := b.NodeInfo()
+	if err != nil {
+		return err
+	}
+
+	names := make(map[int]string, len(nodes))
+	for node := 0; node < len(nodes) && Node(node).Valid(); node++ {
+		if name := strings.TrimSpace(nodes[node].Name); name != "" {
+			names[node] = name
+		}
+	}
+
+	return b.SetNodeNames(names)
+}
+
+// ResolveNode returns the index (0-3) of the node with the given name, as set with SetNodeNames or LoadNodeNames.
+func (b *BMCAPI) ResolveNode(name string) (int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for node, nodeName := range b.nodeNames {
+		if nodeName == name {
+			return node, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown node name %q", name)
+}
+
+// SetPowerByName is SetPower for the node with the given name.
+//
+// Deprecated: Use SetPowerByNameResult, which returns a SetResult.
+func (b *BMCAPI) SetPowerByName(name string, powerState int) (*string, error) {
+	return resultString(b.SetPowerByNameResult(name, powerState))
+}
+
+// SetPowerByNameResult is SetPowerResult for the node with the given name.
+func (b *BMCAPI) SetPowerByNameResult(name string, powerState int) (SetResult, error) {
+	node, err := b.ResolveNode(name)
+	if err != nil {
+		return SetResult{}, err
+	}
+
+	return b.SetPowerResult(node, powerState)
+}
+
+// SetNodeName stores a display name for the specified node (0-3) on the BMC, where it is shown in the web UI and
+// reported by GetNodeName and NodeInfo, so every client sees the same names. Surrounding whitespace and control
+// characters are removed; the name must not be empty or longer than 64 characters after that. Unlike
+// SetNodeNames, it does not make the node addressable by name in this client.
+// Firmware that cannot store node names returns ErrUnsupported.
+func (b *BMCAPI) SetNodeName(node int, name string) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+	// Validate name
+	name = sanitizeNodeName(name)
+	if name == "" {
+		return SetResult{}, fmt.Errorf("name of node %d must not be empty", node)
+	}
+	if utf8.RuneCountInString(name) > maxNodeNameLength {
+		return SetResult{}, fmt.Errorf("name of node %d must not be longer than %d characters", node, maxNodeNameLength)
+	}
+
+	bodyBytes, err := b.capabilityAPICall("node name", "/api/bmc?opt=set&type=node_name&node="+Node(node).queryValue()+"&name="+url.QueryEscape(name))
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during Set Node Name call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
+
+// GetNodeName returns the display name the BMC stores for the specified node (0-3), "" if it has none.
+// It is read from NodeInfo, so older firmware returns ErrUnsupported.
+func (b *BMCAPI) GetNodeName(node int) (string, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return "", ErrInvalidNode
+	}
+
+	nodes, err := b.NodeInfo()
+	if err != nil {
+		return "", err
+	}
+	if node >= len(nodes) {
+		return "", fmt.Errorf("node info response has no entry for node %d", node)
+	}
+
+	return nodes[node].Name, nil
+}
+
+// sanitizeNodeName removes control characters and surrounding whitespace from name.
+func sanitizeNodeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.TrimSpace(name)
+}
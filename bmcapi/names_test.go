@@ -0,0 +1,123 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBMCAPI_SetPowerByName(t *testing.T) {
+	var gotQuery string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		gotQuery = req.URL.RawQuery
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if err := bmc.SetNodeNames(map[int]string{0: "storage", 2: "worker1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := bmc.SetPowerByName("worker1", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "opt=set&type=power&node3=1"; gotQuery != want {
+		t.Errorf("query = %q, want %q", gotQuery, want)
+	}
+
+	if _, err := bmc.SetPowerByName("worker2", 1); err == nil {
+		t.Errorf("expected error for unknown name")
+	}
+
+	if err := bmc.SetNodeNames(map[int]string{0: "a", 1: "a"}); err == nil {
+		t.Errorf("expected error for duplicate names")
+	}
+	if err := bmc.SetNodeNames(map[int]string{4: "a"}); err == nil {
+		t.Errorf("expected error for invalid node")
+	}
+}
+
+func TestBMCAPI_NodeName(t *testing.T) {
+	supported := true
+	names := []string{"", "", "", ""}
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		switch {
+		case !supported:
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		case query.Get("opt") == "set" && query.Get("type") == "node_name":
+			node := int(query.Get("node")[0] - '0')
+			names[node] = query.Get("name")
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		case query.Get("type") == "node_info":
+			return mockResponse(http.StatusOK, `{"response":[{"result":[{"name":"`+names[0]+`"},{"name":"`+names[1]+`"},{},{}]}]}`), nil
+		}
+		t.Errorf("unexpected request: %s", req.URL)
+		return mockResponse(http.StatusNotFound, ""), nil
+	}))
+
+	if result, err := bmc.SetNodeName(1, "  storage\n"); err != nil || !result.Ok() {
+		t.Fatalf("SetNodeName() = %+v, %v, want ok", result, err)
+	}
+	if names[1] != "storage" {
+		t.Errorf("SetNodeName() sent name %q, want it sanitized to %q", names[1], "storage")
+	}
+	if name, err := bmc.GetNodeName(1); err != nil || name != "storage" {
+		t.Errorf("GetNodeName(1) = %q, %v, want storage", name, err)
+	}
+	if name, err := bmc.GetNodeName(0); err != nil || name != "" {
+		t.Errorf("GetNodeName(0) = %q, %v, want no name", name, err)
+	}
+
+	if _, err := bmc.SetNodeName(1, " \t"); err == nil {
+		t.Errorf("expected error for an empty name")
+	}
+	if _, err := bmc.SetNodeName(1, strings.Repeat("n", 65)); err == nil {
+		t.Errorf("expected error for a name that is too long")
+	}
+	if _, err := bmc.SetNodeName(4, "worker"); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("SetNodeName(4) error = %v, want ErrInvalidNode", err)
+	}
+	if _, err := bmc.GetNodeName(-1); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("GetNodeName(-1) error = %v, want ErrInvalidNode", err)
+	}
+
+	supported = false
+	if _, err := bmc.SetNodeName(1, "worker"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetNodeName() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := bmc.GetNodeName(1); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetNodeName() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestBMCAPI_LoadNodeNames(t *testing.T) {
+	body := `{"response":[{"result":[{"name":"storage"},{"name":""},{"name":"worker"},{}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "node_info" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	if err := bmc.LoadNodeNames(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if node, err := bmc.ResolveNode("worker"); err != nil || node != 2 {
+		t.Errorf("ResolveNode(worker) = %d, %v, want 2", node, err)
+	}
+	if node, err := bmc.ResolveNode("storage"); err != nil || node != 0 {
+		t.Errorf("ResolveNode(storage) = %d, %v, want 0", node, err)
+	}
+	if _, err := bmc.ResolveNode(""); err == nil {
+		t.Errorf("expected error for a node without a name")
+	}
+
+	body = `{"response":[{"result":[{"name":"worker"},{"name":"worker"},{},{}]}]}`
+	if err := bmc.LoadNodeNames(); err == nil {
+		t.Errorf("expected error for duplicate firmware names")
+	}
+	if node, err := bmc.ResolveNode("storage"); err != nil || node != 0 {
+		t.Errorf("ResolveNode(storage) after a failed load = %d, %v, want the previous names kept", node, err)
+	}
+}
@@ -0,0 +1,175 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// NetworkConfig is the IP configuration of the BMC's network interface.
+// Fields the firmware does not report are nil or empty.
+type NetworkConfig struct {
+	DHCP    bool
+	IP      net.IP
+	Netmask net.IPMask
+	Gateway net.IP
+	DNS     []net.IP
+}
+
+// NetworkConfig returns the IP configuration of the BMC, including the netmask, gateway and DNS servers
+// that Other does not report. Firmware that does not report its network configuration returns ErrUnsupported.
+func (b *BMCAPI) NetworkConfig() (*NetworkConfig, error) {
+	bodyBytes, err := b.capabilityAPICall("network config", "/api/bmc?opt=get&type=network_config")
+	if err != nil {
+		return nil, fmt.Errorf("error during Network Config call: %w", err)
+	}
+
+	// The configuration is reported as {"response":[{"result":[{"dhcp":<bool>,"ip":"<ip>","netmask":"<mask>","gateway":"<ip>","dns":["<ip>", ...]}] }]}
+	// where dhcp may also be a string and dns a comma or space separated string
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing network config response: %w", err)
+	}
+
+	strs := make(map[string]string, len(result))
+	for key, raw := range result {
+		var text string
+		if json.Unmarshal(raw, &text) == nil {
+			strs[key] = strings.TrimSpace(text)
+		}
+	}
+
+	var config NetworkConfig
+	if raw, ok := result["dhcp"]; ok {
+		if err := json.Unmarshal(raw, &config.DHCP); err != nil {
+			config.DHCP = strings.EqualFold(strs["dhcp"], "true") || strs["dhcp"] == "1"
+		}
+	}
+	if config.IP, err = parseOptionalIP("ip", strs["ip"]); err != nil {
+		return nil, err
+	}
+	if config.Gateway, err = parseOptionalIP("gateway", strs["gateway"]); err != nil {
+		return nil, err
+	}
+	if strs["netmask"] != "" {
+		mask, err := parseOptionalIP("netmask", strs["netmask"])
+		if err != nil {
+			return nil, err
+		}
+		config.Netmask = net.IPMask(mask.To4())
+	}
+
+	dns := strings.FieldsFunc(strs["dns"], func(r rune) bool { return r == ',' || r == ' ' })
+	if _, isText := strs["dns"]; !isText {
+		if raw, ok := result["dns"]; ok {
+			// Some firmware versions report the servers as a JSON array instead of a list in a string
+			if err := json.Unmarshal(raw, &dns); err != nil {
+				return nil, fmt.Errorf("invalid dns servers %s: %w", raw, err)
+			}
+		}
+	}
+	for _, server := range dns {
+		ip, err := parseOptionalIP("dns", server)
+		if err != nil {
+			return nil, err
+		}
+		config.DNS = append(config.DNS, ip)
+	}
+
+	return &config, nil
+}
+
+// SetNetworkConfig configures the BMC's network interface. With DHCP set the addresses are ignored and the BMC
+// requests its address by DHCP; otherwise IP and Netmask must be set and the BMC switches to the static IP.
+// The BMC applies the change immediately and may drop the connection before answering, which is reported as
+// success. The BMCAPI keeps using its old address, so reconnect with a new BMCAPI at the new IP afterwards.
+// Firmware that cannot configure its network returns ErrUnsupported.
+func (b *BMCAPI) SetNetworkConfig(cfg NetworkConfig) error {
+
+	params := url.Values{}
+	if cfg.DHCP {
+		params.Set("dhcp", "true")
+	} else {
+		if err := validateStaticConfig(cfg); err != nil {
+			return fmt.Errorf("invalid network config: %w", err)
+		}
+		params.Set("dhcp", "false")
+		params.Set("ip", cfg.IP.String())
+		params.Set("netmask", net.IP(cfg.Netmask).String())
+		if cfg.Gateway != nil {
+			params.Set("gateway", cfg.Gateway.String())
+		}
+		if len(cfg.DNS) > 0 {
+			dns := make([]string, len(cfg.DNS))
+			for i, server := range cfg.DNS {
+				dns[i] = server.String()
+			}
+			params.Set("dns", strings.Join(dns, ","))
+		}
+	}
+
+	bodyBytes, err := b.capabilityAPICall("network config", "/api/bmc?opt=set&type=network_config&"+params.Encode())
+	if isDroppedConnection(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error during Set Network Config call: %w", err)
+	}
+
+	_, err = b.resultAPIParse(bodyBytes)
+	return err
+
+}
+
+// validateStaticConfig checks that cfg describes a usable static IPv4 configuration.
+func validateStaticConfig(cfg NetworkConfig) error {
+
+	ip := cfg.IP.To4()
+	if ip == nil {
+		return fmt.Errorf("IP must be an IPv4 address, got %v", cfg.IP)
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsMulticast() {
+		return fmt.Errorf("IP %v cannot be assigned to the BMC", cfg.IP)
+	}
+
+	ones, bits := cfg.Netmask.Size()
+	if bits != 32 || ones == 0 {
+		return fmt.Errorf("netmask %v is not a valid IPv4 netmask", net.IP(cfg.Netmask))
+	}
+
+	if cfg.Gateway != nil {
+		subnet := net.IPNet{IP: ip.Mask(cfg.Netmask), Mask: cfg.Netmask}
+		if cfg.Gateway.To4() == nil || !subnet.Contains(cfg.Gateway) {
+			return fmt.Errorf("gateway %v is not in subnet %v", cfg.Gateway, subnet.String())
+		}
+		if cfg.Gateway.Equal(ip) {
+			return fmt.Errorf("gateway %v is the BMC's own IP", cfg.Gateway)
+		}
+	}
+
+	for _, server := range cfg.DNS {
+		if server == nil || server.IsUnspecified() {
+			return fmt.Errorf("invalid DNS server %v", server)
+		}
+	}
+
+	return nil
+
+}
+
+// parseOptionalIP parses value as an IP address, returning nil for an empty or "Unknown" value.
+func parseOptionalIP(field, value string) (net.IP, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == unknownValue {
+		return nil, nil
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid %s address %q", field, value)
+	}
+
+	return ip, nil
+}
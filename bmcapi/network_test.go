@@ -0,0 +1,120 @@
+package bmcapi
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestBMCAPI_NetworkConfig(t *testing.T) {
+	body := `{"response":[{"result":[{"dhcp":true,"ip":"10.0.0.5","netmask":"255.255.255.0","gateway":"10.0.0.1","dns":["10.0.0.1","1.1.1.1"]}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "network_config" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	config, err := bmc.NetworkConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.DHCP || !config.IP.Equal(net.IPv4(10, 0, 0, 5)) || !config.Gateway.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("NetworkConfig() = %+v", config)
+	}
+	if ones, _ := config.Netmask.Size(); ones != 24 {
+		t.Errorf("netmask = %v, want a /24", config.Netmask)
+	}
+	if len(config.DNS) != 2 || !config.DNS[1].Equal(net.IPv4(1, 1, 1, 1)) {
+		t.Errorf("DNS = %v", config.DNS)
+	}
+
+	body = `{"response":[{"result":[{"dhcp":"false","ip":"10.0.0.5","dns":"10.0.0.1, 8.8.8.8"}]}]}`
+	config, err = bmc.NetworkConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.DHCP || len(config.DNS) != 2 || config.Gateway != nil {
+		t.Errorf("NetworkConfig() with string fields = %+v", config)
+	}
+
+	body = `{"response":[{"result":[{"ip":"10.0.0.500"}]}]}`
+	if _, err := bmc.NetworkConfig(); err == nil {
+		t.Errorf("expected error for an invalid IP")
+	}
+
+	body = `{"response":[{"result":[{"ip":"10.0.0.5","dns":{"primary":"10.0.0.1"}}]}]}`
+	if _, err := bmc.NetworkConfig(); err == nil {
+		t.Errorf("expected error for malformed DNS servers")
+	}
+
+	body = `{"response":[{"result":[{"ip":"10.0.0.5","dns":""}]}]}`
+	if config, err := bmc.NetworkConfig(); err != nil || len(config.DNS) != 0 {
+		t.Errorf("NetworkConfig() with no DNS servers = %+v, %v", config, err)
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusNotFound, ""), nil
+	}))
+	if _, err := unsupported.NetworkConfig(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("NetworkConfig() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestBMCAPI_SetNetworkConfig(t *testing.T) {
+	var query url.Values
+	var response func() (*http.Response, error)
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query = req.URL.Query()
+		return response()
+	}))
+
+	static := NetworkConfig{
+		IP:      net.IPv4(10, 0, 0, 5),
+		Netmask: net.CIDRMask(24, 32),
+		Gateway: net.IPv4(10, 0, 0, 1),
+		DNS:     []net.IP{net.IPv4(1, 1, 1, 1), net.IPv4(8, 8, 8, 8)},
+	}
+
+	response = func() (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}
+	if err := bmc.SetNetworkConfig(static); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.Get("type") != "network_config" || query.Get("dhcp") != "false" || query.Get("ip") != "10.0.0.5" ||
+		query.Get("netmask") != "255.255.255.0" || query.Get("gateway") != "10.0.0.1" || query.Get("dns") != "1.1.1.1,8.8.8.8" {
+		t.Errorf("unexpected query: %v", query)
+	}
+
+	// The BMC may drop the connection as soon as it switches address
+	response = func() (*http.Response, error) { return nil, io.EOF }
+	if err := bmc.SetNetworkConfig(static); err != nil {
+		t.Errorf("SetNetworkConfig() with dropped connection = %v, want nil", err)
+	}
+
+	if err := bmc.SetNetworkConfig(NetworkConfig{DHCP: true}); err != nil || query.Get("dhcp") != "true" || query.Has("ip") {
+		t.Errorf("SetNetworkConfig(DHCP) = %v, query %v", err, query)
+	}
+
+	query = nil
+	invalid := []NetworkConfig{
+		{Netmask: net.CIDRMask(24, 32)},
+		{IP: net.ParseIP("fe80::1"), Netmask: net.CIDRMask(24, 32)},
+		{IP: net.IPv4(10, 0, 0, 5)},
+		{IP: net.IPv4(10, 0, 0, 5), Netmask: net.IPv4Mask(255, 0, 255, 0)},
+		{IP: net.IPv4(10, 0, 0, 5), Netmask: net.CIDRMask(24, 32), Gateway: net.IPv4(10, 0, 1, 1)},
+		{IP: net.IPv4(10, 0, 0, 5), Netmask: net.CIDRMask(24, 32), DNS: []net.IP{nil}},
+	}
+	for _, cfg := range invalid {
+		if err := bmc.SetNetworkConfig(cfg); err == nil {
+			t.Errorf("SetNetworkConfig(%+v) expected error", cfg)
+		}
+	}
+	if query != nil {
+		t.Errorf("invalid config was sent to the BMC: %v", query)
+	}
+}
@@ -0,0 +1,48 @@
+package bmcapi
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Node identifies one of the four node slots of the Turing Pi 2.
+//
+// The SDK numbers nodes 0-3 everywhere; Node(0) is the slot labelled "Node 1" on the board. The firmware is
+// not consistent about this: the node query parameter is 0-based (node=0), but the keys of power status and
+// power set requests are 1-based (node1 to node4). Node converts between the two representations, so the
+// firmware's numbering only needs to be known here.
+type Node int
+
+// NodeFromOneBased returns the Node for a 1-based number (1-4) as used by the firmware's keys and the board labels.
+func NodeFromOneBased(n int) (Node, error) {
+	node := Node(n - 1)
+	if !node.Valid() {
+		return 0, fmt.Errorf("node %d: %w", n, ErrInvalidNode)
+	}
+	return node, nil
+}
+
+// Valid reports whether n is one of the four nodes (0-3).
+func (n Node) Valid() bool {
+	return n >= 0 && n <= 3
+}
+
+// ZeroBased returns the node number (0-3) as used by the SDK and the firmware's node query parameter.
+func (n Node) ZeroBased() int {
+	return int(n)
+}
+
+// OneBased returns the node number (1-4) as used by the firmware's power keys and the board labels.
+func (n Node) OneBased() int {
+	return int(n) + 1
+}
+
+// queryValue returns the value of the firmware's node query parameter for n, e.g. "0" for Node(0).
+func (n Node) queryValue() string {
+	return strconv.Itoa(n.ZeroBased())
+}
+
+// key returns the firmware's key for n in power status and power set requests, e.g. "node1" for Node(0).
+func (n Node) key() string {
+	return "node" + strconv.Itoa(n.OneBased())
+}
@@ -0,0 +1,32 @@
+package bmcapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNode(t *testing.T) {
+	for n := Node(0); n <= 3; n++ {
+		if !n.Valid() || n.ZeroBased() != int(n) || n.OneBased() != int(n)+1 {
+			t.Errorf("Node(%d): Valid %v, ZeroBased %d, OneBased %d", n, n.Valid(), n.ZeroBased(), n.OneBased())
+		}
+		back, err := NodeFromOneBased(n.OneBased())
+		if err != nil || back != n {
+			t.Errorf("NodeFromOneBased(%d) = %d, %v, want %d", n.OneBased(), back, err, n)
+		}
+	}
+	if Node(0).key() != "node1" || Node(3).queryValue() != "3" {
+		t.Errorf("key = %s, queryValue = %s", Node(0).key(), Node(3).queryValue())
+	}
+
+	for _, n := range []Node{-1, 4} {
+		if n.Valid() {
+			t.Errorf("Node(%d).Valid() = true", n)
+		}
+	}
+	for _, n := range []int{0, 5} {
+		if _, err := NodeFromOneBased(n); !errors.Is(err, ErrInvalidNode) {
+			t.Errorf("NodeFromOneBased(%d) error = %v, want ErrInvalidNode", n, err)
+		}
+	}
+}
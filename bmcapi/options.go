@@ -0,0 +1,263 @@
+package bmcapi
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures optional behaviour of a BMCAPI. Options are passed to NewBMCAPI.
+type Option func(*BMCAPI) error
+
+// WithLogger logs every request made to the BMC at debug level: method, URL, status code and duration.
+// Credentials are sent in headers and are never logged; sensitive query parameters are redacted.
+// Without a logger nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *BMCAPI) error {
+		if logger == nil {
+			return fmt.Errorf("logger must not be nil")
+		}
+		b.logger = logger
+		return nil
+	}
+}
+
+// WithRebootCommand sets the command RebootNodeOS sends over the serial console, e.g. "sudo reboot"
+// or "shutdown -r now", for operating systems where plain "reboot" is not right.
+func WithRebootCommand(cmd string) Option {
+	return func(b *BMCAPI) error {
+		if cmd == "" {
+			return fmt.Errorf("reboot command must not be empty")
+		}
+		b.rebootCommand = cmd
+		return nil
+	}
+}
+
+// WithAPIPrefix sets the path the BMC API is served under, replacing the firmware's "/api/bmc",
+// e.g. "/turingpi/api/bmc" when a reverse proxy exposes the BMC under a subpath.
+// Leading and trailing slashes are normalized, so "turingpi/api/bmc/" works the same.
+func WithAPIPrefix(prefix string) Option {
+	return func(b *BMCAPI) error {
+		prefix = strings.Trim(prefix, "/")
+		if prefix != "" {
+			prefix = "/" + prefix
+		}
+		if strings.ContainsAny(prefix, "?#") {
+			return fmt.Errorf("API prefix must be a plain path")
+		}
+		b.apiPrefix = prefix
+		b.customAPIPrefix = true
+		return nil
+	}
+}
+
+// WithForceHTTPS rewrites an http:// base URL, and any fallback URLs, to https:// when the client is created.
+// The BMC only serves its API over HTTPS, so an http URL is almost always a mistake that otherwise fails in
+// confusing ways. An explicit port 80 is dropped in favor of the HTTPS default. Each upgrade is logged at
+// info level if a logger is set.
+func WithForceHTTPS() Option {
+	return func(b *BMCAPI) error {
+		b.forceHTTPS = true
+		return nil
+	}
+}
+
+// upgradeToHTTPS is a helper function for WithForceHTTPS that rewrites the base and fallback URLs of b to https.
+func (b *BMCAPI) upgradeToHTTPS() {
+
+	upgrade := func(baseURL string) string {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme != "http" {
+			return baseURL
+		}
+		u.Scheme = "https"
+		if u.Port() == "80" {
+			u.Host = u.Hostname()
+			if strings.Contains(u.Host, ":") {
+				u.Host = "[" + u.Host + "]"
+			}
+		}
+		if b.logger != nil {
+			display := *u
+			display.User = nil
+			b.logger.Info("upgraded bmc URL to https", slog.String("url", display.String()))
+		}
+		return u.String()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.BaseURL = upgrade(b.BaseURL)
+	b.primaryURL = upgrade(b.primaryURL)
+	for i, fallback := range b.fallbackURLs {
+		b.fallbackURLs[i] = upgrade(fallback)
+	}
+
+}
+
+// WithLazyAuth makes NewBMCAPI return without contacting the BMC, so a client can be created before
+// the BMC is reachable. Authentication happens on the first API call instead, or explicitly with Authenticate.
+// With basic auth the credentials are then only checked by the first call.
+func WithLazyAuth() Option {
+	return func(b *BMCAPI) error {
+		b.lazyAuth = true
+		return nil
+	}
+}
+
+// WithBearerToken makes a bearer auth client use token, e.g. one shared by another process, instead of
+// requesting one from the BMC. NewBMCAPI then does not contact the BMC, and username and password may be empty.
+// If they are given, Authenticate can request a new token once the supplied one expires.
+func WithBearerToken(token string) Option {
+	return func(b *BMCAPI) error {
+		if b.AuthType != "bearer" {
+			return fmt.Errorf("a bearer token requires bearer auth")
+		}
+		if token == "" {
+			return fmt.Errorf("bearer token must not be empty")
+		}
+		b.bearerToken = token
+		return nil
+	}
+}
+
+// WithStrictTLS makes b verify the BMC's TLS certificate, undoing the self-signed certificate workaround
+// of NewInsecureClient. Use it when the BMC serves a trusted certificate, e.g. through a reverse proxy.
+// The client passed to NewBMCAPI is copied, not modified, and its transport replaced by a verifying one.
+func WithStrictTLS() Option {
+	return func(b *BMCAPI) error {
+		b.setTLSTransport(nil)
+		b.strictTLS = true
+		return nil
+	}
+}
+
+// WithInsecureTLS makes b skip verification of the BMC's TLS certificate, as NewInsecureClient does,
+// for BMCs that serve their stock self-signed certificate. Like WithStrictTLS it copies the client
+// passed to NewBMCAPI and replaces its transport.
+func WithInsecureTLS() Option {
+	return func(b *BMCAPI) error {
+		b.setTLSTransport(&tls.Config{InsecureSkipVerify: true}) // Skip TLS verification for self-signed certs
+		return nil
+	}
+}
+
+// setTLSTransport replaces b.Client with a copy that uses the given TLS configuration. The client's transport
+// is copied with it if it is an *http.Transport, e.g. one set with WithTransport; otherwise a default one is used.
+func (b *BMCAPI) setTLSTransport(config *tls.Config) {
+	current, ok := b.Client.Transport.(*http.Transport)
+	if !ok {
+		current = http.DefaultTransport.(*http.Transport)
+	}
+	transport := current.Clone()
+	transport.TLSClientConfig = config
+
+	client := *b.Client
+	client.Transport = transport
+	b.Client = &client
+}
+
+// WithTransport makes b send requests through transport, e.g. one from NewTransport tuned to reuse connections
+// across many requests. The client passed to NewBMCAPI and transport are copied, not modified. If the client
+// skips certificate verification, as the default client does for the BMC's self-signed certificate, so does
+// the new transport; give WithStrictTLS after WithTransport to verify certificates.
+func WithTransport(transport *http.Transport) Option {
+	return func(b *BMCAPI) error {
+		if transport == nil {
+			return fmt.Errorf("transport must not be nil")
+		}
+
+		transport = transport.Clone()
+		if current, ok := b.Client.Transport.(*http.Transport); ok && current.TLSClientConfig != nil && current.TLSClientConfig.InsecureSkipVerify {
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+			transport.TLSClientConfig.InsecureSkipVerify = true // Skip TLS verification for self-signed certs
+		}
+
+		client := *b.Client
+		client.Transport = transport
+		b.Client = &client
+		return nil
+	}
+}
+
+// WithDefaultHeaders adds headers to every request to the BMC, including authentication requests, e.g. the API key
+// a gateway or WAF in front of the BMC requires. Headers the SDK sets itself, such as Authorization and Content-Type,
+// take precedence, and Authorization cannot be given at all. When used more than once, the headers are merged.
+func WithDefaultHeaders(headers map[string]string) Option {
+	return func(b *BMCAPI) error {
+		if b.defaultHeaders == nil {
+			b.defaultHeaders = make(http.Header, len(headers))
+		}
+		for name, value := range headers {
+			if name == "" || strings.ContainsAny(name, " :\r\n") {
+				return fmt.Errorf("invalid header name %q", name)
+			}
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("value of header %s must not contain line breaks", name)
+			}
+			if http.CanonicalHeaderKey(name) == "Authorization" {
+				return fmt.Errorf("the Authorization header is set by the SDK and cannot be a default header")
+			}
+			b.defaultHeaders.Set(name, value)
+		}
+		return nil
+	}
+}
+
+// WithRequestInterceptor calls intercept on every request to the BMC, including authentication requests,
+// right before it is sent and after the auth headers were set, e.g. to add headers an auth proxy needs.
+// When used more than once, interceptors run in the order they were given. An error from an interceptor
+// aborts the request and is returned by the method that made it.
+func WithRequestInterceptor(intercept func(*http.Request) error) Option {
+	return func(b *BMCAPI) error {
+		if intercept == nil {
+			return fmt.Errorf("request interceptor must not be nil")
+		}
+		b.requestInterceptors = append(b.requestInterceptors, intercept)
+		return nil
+	}
+}
+
+// WithResponseInterceptor calls observe after every request to the BMC with the response, the time the request
+// took and the error, e.g. to record latency and status codes as metrics. It is also called when the request
+// failed, with a nil response. The response body must not be read or closed by observe.
+// When used more than once, interceptors run in the order they were given.
+func WithResponseInterceptor(observe func(*http.Response, time.Duration, error)) Option {
+	return func(b *BMCAPI) error {
+		if observe == nil {
+			return fmt.Errorf("response interceptor must not be nil")
+		}
+		b.responseInterceptors = append(b.responseInterceptors, observe)
+		return nil
+	}
+}
+
+// sensitiveQueryParams are query parameters whose values are replaced by redactURL.
+// UART commands are included as they may contain passwords typed into a login prompt.
+var sensitiveQueryParams = []string{"password", "token", "cmd"}
+
+// redactURL returns u as a string with any password and sensitive query parameter values redacted.
+func redactURL(u *url.URL) string {
+	redacted := *u
+
+	query := redacted.Query()
+	changed := false
+	for _, key := range sensitiveQueryParams {
+		if query.Has(key) {
+			query.Set(key, "REDACTED")
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.Redacted()
+}
@@ -0,0 +1,323 @@
+package bmcapi
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+	bmc, err := NewBMCAPI("http://mock", "basic", "user", "secret-password", client, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := bmc.RawGet("/api/bmc?opt=set&type=uart&node=0&cmd=hunter2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out := logs.String()
+	if !strings.Contains(out, "type=info") || !strings.Contains(out, "status=200") {
+		t.Errorf("expected the auth probe to be logged with its status, got:\n%s", out)
+	}
+	if !strings.Contains(out, "cmd=REDACTED") {
+		t.Errorf("expected the UART command to be redacted, got:\n%s", out)
+	}
+	for _, secret := range []string{"secret-password", "hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains secret %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestWithAPIPrefix(t *testing.T) {
+	var paths []string
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		if req.URL.Path == "/turingpi/api/bmc/authenticate" {
+			return mockResponse(http.StatusOK, `{"id":"token"}`), nil
+		}
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	})}
+
+	bmc, err := NewBMCAPI("https://proxy.example/", "bearer", "user", "pass", client, WithAPIPrefix("turingpi/api/bmc/"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.GetPower(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"/turingpi/api/bmc/authenticate", "/turingpi/api/bmc"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("request paths = %v, want %v", paths, want)
+	}
+
+	if got := bmc.endpointURL("/api/bmc?opt=get&type=other"); got != "https://proxy.example/turingpi/api/bmc?opt=get&type=other" {
+		t.Errorf("endpointURL() = %q", got)
+	}
+}
+
+func TestWithBearerToken(t *testing.T) {
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			t.Errorf("authenticated despite WithBearerToken")
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer shared-token" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer shared-token")
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+
+	bmc, err := NewBMCAPI("http://mock", "bearer", "", "", client, WithBearerToken("shared-token"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.USBBootResult(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := NewBMCAPI("http://mock", "basic", "user", "pass", client, WithBearerToken("shared-token")); err == nil {
+		t.Errorf("expected error for a bearer token with basic auth")
+	}
+}
+
+func TestWithForceHTTPS(t *testing.T) {
+	var urls []string
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.Scheme+"://"+req.URL.Host)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	bmc, err := NewBMCAPI("http://turingpi.local:80", "basic", "user", "pass", client,
+		WithForceHTTPS(), WithLogger(logger), WithFallbackURLs("http://[fd00::2]:80", "https://192.168.1.50"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bmc.BaseURL != "https://turingpi.local" {
+		t.Errorf("BaseURL = %q, want https://turingpi.local", bmc.BaseURL)
+	}
+	if want := []string{"https://[fd00::2]", "https://192.168.1.50"}; !slices.Equal(bmc.fallbackURLs, want) {
+		t.Errorf("fallback URLs = %v, want %v", bmc.fallbackURLs, want)
+	}
+	if want := []string{"https://turingpi.local"}; !slices.Equal(urls, want) {
+		t.Errorf("requests went to %v, want %v", urls, want)
+	}
+	if !strings.Contains(logs.String(), "upgraded bmc URL to https") {
+		t.Errorf("upgrade was not logged: %s", logs.String())
+	}
+
+	bmc, err = NewBMCAPI("http://mock:8080", "basic", "user", "pass", client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bmc.BaseURL != "http://mock:8080" {
+		t.Errorf("BaseURL without WithForceHTTPS = %q, want it unchanged", bmc.BaseURL)
+	}
+	clone, err := bmc.Clone(WithForceHTTPS())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if clone.BaseURL != "https://mock:8080" {
+		t.Errorf("clone BaseURL = %q, want https://mock:8080", clone.BaseURL)
+	}
+}
+
+func TestWithStrictTLS(t *testing.T) {
+	insecure := NewInsecureClient()
+	bmc, err := NewBMCAPI("https://turingpi.local", "basic", "user", "pass", insecure, WithLazyAuth(), WithStrictTLS())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	transport := bmc.Client.Transport.(*http.Transport)
+	if transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("WithStrictTLS client skips certificate verification")
+	}
+	if !insecure.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("WithStrictTLS modified the client passed to NewBMCAPI")
+	}
+
+	bmc, err = NewBMCAPI("https://turingpi.local", "basic", "user", "pass", &http.Client{}, WithLazyAuth(), WithInsecureTLS())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bmc.Client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("WithInsecureTLS client verifies certificates")
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	tuned := NewTransport()
+	tuned.MaxIdleConnsPerHost = 8
+
+	bmc, err := NewBMCAPI("https://turingpi.local", "basic", "user", "pass", nil, WithLazyAuth(), WithTransport(tuned))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transport := bmc.Client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 8 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want the tuned transport's 8", transport.MaxIdleConnsPerHost)
+	}
+	if transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("WithTransport dropped the default client's self-signed certificate workaround")
+	}
+	if tuned.TLSClientConfig != nil && tuned.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("WithTransport modified the transport passed to it")
+	}
+
+	// WithStrictTLS keeps the tuning
+	bmc, err = NewBMCAPI("https://turingpi.local", "basic", "user", "pass", nil, WithLazyAuth(), WithTransport(tuned), WithStrictTLS())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transport = bmc.Client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 8 || (transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify) {
+		t.Errorf("WithStrictTLS after WithTransport = %d idle per host, TLS %+v", transport.MaxIdleConnsPerHost, transport.TLSClientConfig)
+	}
+}
+
+func TestWithDefaultHeaders(t *testing.T) {
+	var got []http.Header
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		got = append(got, req.Header.Clone())
+		if req.URL.Path == "/api/bmc/authenticate" {
+			return mockResponse(http.StatusOK, `{"id":"token"}`), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+
+	bmc, err := NewBMCAPI("http://mock", "bearer", "user", "pass", client,
+		WithDefaultHeaders(map[string]string{"x-api-key": "gateway", "Content-Type": "text/plain"}),
+		WithDefaultHeaders(map[string]string{"X-Tenant": "lab"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clone, err := bmc.Clone()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := clone.USBBootResult(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d requests, want the authentication request and one call", len(got))
+	}
+	for i, header := range got {
+		if header.Get("X-Api-Key") != "gateway" || header.Get("X-Tenant") != "lab" {
+			t.Errorf("request %d sent without the default headers: %v", i, header)
+		}
+		if header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %d Content-Type = %q, want the SDK's", i, header.Get("Content-Type"))
+		}
+	}
+	if got[1].Get("Authorization") != "Bearer token" {
+		t.Errorf("Authorization = %q, want the bearer token", got[1].Get("Authorization"))
+	}
+
+	for _, headers := range []map[string]string{
+		{"authorization": "Bearer other"},
+		{"": "value"},
+		{"X-Bad Name": "value"},
+		{"X-Key": "a\r\nX-Injected: b"},
+	} {
+		if _, err := NewBMCAPI("http://mock", "basic", "user", "pass", client, WithDefaultHeaders(headers)); err == nil {
+			t.Errorf("WithDefaultHeaders(%q) expected error", headers)
+		}
+	}
+}
+
+func TestWithRequestInterceptor(t *testing.T) {
+	var order []string
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("X-Proxy-Auth") != "secret" {
+			t.Errorf("request sent without the interceptor's header")
+		}
+		if _, _, ok := req.BasicAuth(); !ok {
+			t.Errorf("request sent without auth headers")
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+
+	blocked := false
+	bmc, err := NewBMCAPI("http://mock", "basic", "user", "pass", client,
+		WithRequestInterceptor(func(req *http.Request) error {
+			order = append(order, "first")
+			req.Header.Set("X-Proxy-Auth", "secret")
+			return nil
+		}),
+		WithRequestInterceptor(func(req *http.Request) error {
+			order = append(order, "second")
+			if blocked {
+				return errors.New("blocked")
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(order, []string{"first", "second"}) {
+		t.Errorf("interceptors ran in order %v", order)
+	}
+
+	blocked = true
+	if _, err := bmc.USBBootResult(0); err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Errorf("USBBootResult() error = %v, want the interceptor's error", err)
+	}
+}
+
+func TestWithResponseInterceptor(t *testing.T) {
+	fail := false
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if fail {
+			return nil, errors.New("connection reset")
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	type observation struct {
+		status int
+		err    error
+	}
+	var observed []observation
+	if err := WithResponseInterceptor(func(resp *http.Response, elapsed time.Duration, err error) {
+		o := observation{err: err}
+		if resp != nil {
+			o.status = resp.StatusCode
+		}
+		if elapsed <= 0 {
+			t.Errorf("elapsed = %v, want a positive duration", elapsed)
+		}
+		observed = append(observed, o)
+	})(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := bmc.USBBootResult(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fail = true
+	if _, err := bmc.USBBootResult(0); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if len(observed) != 2 || observed[0].status != http.StatusOK || observed[0].err != nil || observed[1].err == nil {
+		t.Errorf("observed %+v, want a 200 response and then an error", observed)
+	}
+}
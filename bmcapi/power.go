@@ -0,0 +1,351 @@
+package bmcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PowerState is the power state of a node.
+type PowerState int
+
+// The power states a node can be set to. They have the values SetPower takes.
+const (
+	PowerOff PowerState = 0
+	PowerOn  PowerState = 1
+)
+
+// String returns "on" or "off", or PowerState(n) for an invalid state.
+func (s PowerState) String() string {
+	switch s {
+	case PowerOff:
+		return "off"
+	case PowerOn:
+		return "on"
+	}
+	return "PowerState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// SetNodePower sets the power of the specified node (0-3) to state. It is SetPowerResult with a typed state.
+func (b *BMCAPI) SetNodePower(node int, state PowerState) (SetResult, error) {
+	// Validate state
+	if state != PowerOff && state != PowerOn {
+		return SetResult{}, ErrInvalidPowerState
+	}
+
+	return b.SetPowerResult(node, int(state))
+}
+
+// PowerStates returns the power state of each node, indexed by node (0-3) like the rest of the SDK,
+// translating the firmware's 1-based keys.
+func (b *BMCAPI) PowerStates() ([4]PowerState, error) {
+	return b.powerStates(context.Background())
+}
+
+// powerStates is PowerStates with the request made under ctx.
+func (b *BMCAPI) powerStates(ctx context.Context) ([4]PowerState, error) {
+	var states [4]PowerState
+
+	power, _, err := b.getPowerWithResponse(ctx)
+	if err != nil {
+		return states, err
+	}
+
+	for node := range states {
+		on, err := nodePowerState(power, node)
+		if err != nil {
+			return states, err
+		}
+		if on {
+			states[node] = PowerOn
+		}
+	}
+
+	return states, nil
+}
+
+// IsNodeOn reports whether the specified node (0-3) is powered on.
+// The firmware reports power for nodes 1-4, so node 0 is read from the "node1" entry.
+func (b *BMCAPI) IsNodeOn(node int) (bool, error) {
+	return b.isNodeOn(context.Background(), node)
+}
+
+// isNodeOn is IsNodeOn with the request made under ctx.
+func (b *BMCAPI) isNodeOn(ctx context.Context, node int) (bool, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return false, ErrInvalidNode
+	}
+
+	power, _, err := b.getPowerWithResponse(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return nodePowerState(power, node)
+}
+
+// WaitForNodePower polls the power status every poll interval (one second if poll is 0)
+// until the specified node (0-3) is on (want true) or off (want false), or ctx expires.
+// It returns early with the error of a failed status query.
+func (b *BMCAPI) WaitForNodePower(ctx context.Context, node int, want bool, poll time.Duration) error {
+	// Validate node number
+	if !Node(node).Valid() {
+		return ErrInvalidNode
+	}
+
+	err := b.pollUntil(ctx, poll, func() (bool, error) {
+		on, err := b.isNodeOn(ctx, node)
+		return on == want, err
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for node %d to power %s: %w", node, powerStateName(want), err)
+	}
+
+	return nil
+}
+
+// powerStateName returns "on" or "off" for use in messages.
+func powerStateName(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// EnsurePower powers the specified node (0-3) on (want true) or off (want false) unless it already is,
+// and reports whether a change was requested. The current state is read with GetPower first.
+func (b *BMCAPI) EnsurePower(node int, want bool) (bool, error) {
+	on, err := b.IsNodeOn(node)
+	if err != nil {
+		return false, err
+	}
+	if on == want {
+		return false, nil
+	}
+
+	powerState := 0
+	if want {
+		powerState = 1
+	}
+	if _, err := b.SetPowerResult(node, powerState); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ToggleNodePower powers the specified node (0-3) off if it is on and on if it is off, and returns whether it is
+// now on. The current state is read with GetPower first, so a change made in between by someone else is undone.
+func (b *BMCAPI) ToggleNodePower(node int) (bool, error) {
+	on, err := b.IsNodeOn(node)
+	if err != nil {
+		return false, err
+	}
+
+	state := PowerOn
+	if on {
+		state = PowerOff
+	}
+	if _, err := b.SetNodePower(node, state); err != nil {
+		return on, err
+	}
+
+	return !on, nil
+}
+
+// SetPowerConfirmed sets the power of the specified node (0-3), like SetNodePower, and then waits up to timeout
+// for GetPower to report the requested state. This catches the firmware accepting a power change that does not
+// happen. If the state is not reached in time, the returned error includes the state that was last observed.
+func (b *BMCAPI) SetPowerConfirmed(node int, state PowerState, timeout time.Duration) error {
+	if _, err := b.SetNodePower(node, state); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	want := state == PowerOn
+	err := b.WaitForNodePower(ctx, node, want, 0)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	on, readErr := b.IsNodeOn(node)
+	if readErr != nil {
+		return fmt.Errorf("node %d did not power %s within %v: %w", node, powerStateName(want), timeout, err)
+	}
+	return fmt.Errorf("node %d did not power %s within %v, it is still %s", node, powerStateName(want), timeout, powerStateName(on))
+}
+
+// PowerOnSequence powers nodes on one at a time in the given order, waiting delay between nodes,
+// to avoid the current spike of starting all nodes at once. Without an order, nodes 0-3 are powered on in ascending order.
+// All nodes are validated before any is powered on. It stops at the first failure and reports the node that failed.
+func (b *BMCAPI) PowerOnSequence(delay time.Duration, order ...int) error {
+	if len(order) == 0 {
+		order = []int{0, 1, 2, 3}
+	}
+
+	seen := make(map[int]bool, len(order))
+	for _, node := range order {
+		// Validate node number
+		if !Node(node).Valid() {
+			return fmt.Errorf("invalid node %d in power on sequence: %w", node, ErrInvalidNode)
+		}
+		if seen[node] {
+			return fmt.Errorf("node %d appears more than once in power on sequence", node)
+		}
+		seen[node] = true
+	}
+
+	for i, node := range order {
+		if i > 0 {
+			time.Sleep(delay)
+		}
+		if _, err := b.SetPowerResult(node, 1); err != nil {
+			return fmt.Errorf("powering on node %d: %w", node, err)
+		}
+	}
+
+	return nil
+}
+
+// SetAutoPowerOn sets whether the specified node (0-3) powers on by itself when the board gets power,
+// e.g. after a power outage at an unattended site. Firmware that does not support it returns ErrUnsupported.
+func (b *BMCAPI) SetAutoPowerOn(node int, enabled bool) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+	bodyBytes, err := b.capabilityAPICall("auto power on", "/api/bmc?opt=set&type=auto_power_on&node="+Node(node).queryValue()+"&enabled="+value)
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during Set Auto Power On call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
+
+// GetAutoPowerOn reports whether the specified node (0-3) powers on by itself when the board gets power.
+// Firmware that does not support it returns ErrUnsupported.
+func (b *BMCAPI) GetAutoPowerOn(node int) (bool, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return false, ErrInvalidNode
+	}
+
+	bodyBytes, err := b.capabilityAPICall("auto power on", "/api/bmc?opt=get&type=auto_power_on")
+	if err != nil {
+		return false, fmt.Errorf("error during Get Auto Power On call: %w", err)
+	}
+
+	// The settings are keyed by node like the power status, {"response":[{"result":[{"node1":<enabled>, ...}] }]}
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return false, fmt.Errorf("error parsing auto power on response: %w", err)
+	}
+
+	key := Node(node).key()
+	raw, ok := result[key]
+	if !ok {
+		return false, fmt.Errorf("auto power on response has no entry for node %d (%s)", node, key)
+	}
+
+	return parsePowerValue(raw)
+}
+
+// nodePowerState looks up the specified node (0-3) in a GetPower result.
+func nodePowerState(power map[string]string, node int) (bool, error) {
+	key := Node(node).key()
+
+	value, ok := power[key]
+	if !ok {
+		return false, fmt.Errorf("power status response has no entry for node %d (%s)", node, key)
+	}
+
+	return parsePowerState(value)
+}
+
+// bmcPowerAPIResponse is a struct that represents the response from the BMC API for the power endpoint.
+// It expects the response to be in the format {"response":[{"result":[{"node1":<state>, ...}] }]}
+// where a state may be a string or a JSON boolean or number.
+type bmcPowerAPIResponse struct {
+	Response []struct {
+		Result []map[string]json.RawMessage `json:"result"`
+	} `json:"response"`
+}
+
+// powerAPIParse is a helper function that parses a power status response into a map of
+// node keys to "1" (on) or "0" (off), accepting every representation known to be used by the firmware.
+func powerAPIParse(bodyBytes []byte) (map[string]string, error) {
+
+	var parsed bmcPowerAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing json in power response: %w", err)
+	}
+	if len(parsed.Response) == 0 || len(parsed.Response[0].Result) == 0 {
+		return nil, fmt.Errorf("no data in response")
+	}
+
+	power := make(map[string]string, len(parsed.Response[0].Result[0]))
+	for key, raw := range parsed.Response[0].Result[0] {
+		on, err := parsePowerValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		power[key] = "0"
+		if on {
+			power[key] = "1"
+		}
+	}
+
+	return power, nil
+
+}
+
+// parsePowerValue converts a JSON power state into a bool.
+// Strings are handled by parsePowerState; booleans and the numbers 0 and 1 are accepted as well.
+func parsePowerValue(raw json.RawMessage) (bool, error) {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return false, fmt.Errorf("invalid power state %s: %w", raw, err)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return parsePowerState(v)
+	case bool:
+		return v, nil
+	case float64:
+		if v == 0 || v == 1 {
+			return v == 1, nil
+		}
+	}
+
+	return false, fmt.Errorf("unknown power state %s", raw)
+}
+
+// parsePowerState converts a power state reported by the firmware as a string into a bool.
+// It accepts "1"/"0", "on"/"off" and "true"/"false" in any case.
+func parsePowerState(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "on", "true":
+		return true, nil
+	case "0", "off", "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unknown power state %q", value)
+}
@@ -0,0 +1,374 @@
+package bmcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const mockPowerResponse = `{"response":[{"result":[{"node1":"1","node2":"0","node3":"1","node4":"0"}]}]}`
+
+func TestBMCAPI_IsNodeOn(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	want := []bool{true, false, true, false}
+	for node, wantOn := range want {
+		on, err := bmc.IsNodeOn(node)
+		if err != nil {
+			t.Fatalf("IsNodeOn(%d) unexpected error: %v", node, err)
+		}
+		if on != wantOn {
+			t.Errorf("IsNodeOn(%d) = %v, want %v", node, on, wantOn)
+		}
+	}
+
+	if _, err := bmc.IsNodeOn(4); err == nil {
+		t.Errorf("expected error for invalid node")
+	}
+}
+
+func TestBMCAPI_IsNodeOn_MissingEntry(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"node1":"1"}]}]}`), nil
+	}))
+
+	if _, err := bmc.IsNodeOn(2); err == nil {
+		t.Errorf("expected error when the response lacks the node")
+	}
+}
+
+func TestBMCAPI_WaitForNodePower(t *testing.T) {
+	calls := 0
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return mockResponse(http.StatusOK, `{"response":[{"result":[{"node1":"0","node2":"0","node3":"0","node4":"0"}]}]}`), nil
+		}
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	if err := bmc.WaitForNodePower(context.Background(), 0, true, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("made %d power queries, want 3", calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bmc.WaitForNodePower(ctx, 1, true, time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForNodePower() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestBMCAPI_WaitForNodePower_Context(t *testing.T) {
+	type traceKey struct{}
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.Context().Value(traceKey{}) != "span" {
+			t.Errorf("request was not made under the caller's context")
+		}
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "span")
+	if err := bmc.WaitForNodePower(ctx, 0, true, time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBMCAPI_SetNodePower(t *testing.T) {
+	var got string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		got = req.URL.RawQuery
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if _, err := bmc.SetNodePower(2, PowerOn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	typed := got
+	if _, err := bmc.SetPowerResult(2, 1); err != nil || got != typed {
+		t.Errorf("SetNodePower(2, PowerOn) sent %s, SetPowerResult(2, 1) sent %s", typed, got)
+	}
+
+	if _, err := bmc.SetNodePower(2, PowerState(2)); !errors.Is(err, ErrInvalidPowerState) {
+		t.Errorf("SetNodePower(2, 2) error = %v, want ErrInvalidPowerState", err)
+	}
+	if PowerOn.String() != "on" || PowerState(5).String() != "PowerState(5)" {
+		t.Errorf("unexpected PowerState strings %q, %q", PowerOn, PowerState(5))
+	}
+}
+
+// powerStateTransport is a mock that keeps power state like the firmware: power set requests and
+// status responses both key nodes by their 1-based number.
+func powerStateTransport() mockTransport {
+	power := map[string]string{"node1": "0", "node2": "0", "node3": "0", "node4": "0"}
+	return func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("opt") == "set" && query.Get("type") == "power" {
+			for key := range power {
+				if value := query.Get(key); value != "" {
+					power[key] = value
+				}
+			}
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		}
+		body, _ := json.Marshal(map[string]any{"response": []any{map[string]any{"result": []any{power}}}})
+		return mockResponse(http.StatusOK, string(body)), nil
+	}
+}
+
+func TestBMCAPI_PowerRoundTrip(t *testing.T) {
+	for node := range 4 {
+		bmc := newMockBMCAPI(powerStateTransport())
+
+		if _, err := bmc.SetPower(node, 1); err != nil {
+			t.Fatalf("SetPower(%d, 1) unexpected error: %v", node, err)
+		}
+
+		power, err := bmc.GetPower()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if key := "node" + strconv.Itoa(node+1); power[key] != "1" {
+			t.Errorf("after SetPower(%d, 1) GetPower() = %v, want %s on", node, power, key)
+		}
+
+		states, err := bmc.PowerStates()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for other, state := range states {
+			if want := other == node; (state == PowerOn) != want {
+				t.Errorf("after SetPower(%d, 1) PowerStates()[%d] = %v", node, other, state)
+			}
+		}
+		if on, err := bmc.IsNodeOn(node); err != nil || !on {
+			t.Errorf("after SetPower(%d, 1) IsNodeOn(%d) = %v, %v, want true", node, node, on, err)
+		}
+	}
+}
+
+func TestBMCAPI_SetPowerConfirmed(t *testing.T) {
+	applied := true
+	power := `{"response":[{"result":[{"node1":"0","node2":"0","node3":"0","node4":"0"}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("opt") == "get" {
+			return mockResponse(http.StatusOK, power), nil
+		}
+		if applied {
+			power = `{"response":[{"result":[{"node1":"0","node2":"1","node3":"0","node4":"0"}]}]}`
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if err := bmc.SetPowerConfirmed(1, PowerOn, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The firmware accepts the request but the node stays on
+	applied = false
+	err := bmc.SetPowerConfirmed(1, PowerOff, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "still on") {
+		t.Errorf("SetPowerConfirmed() error = %v, want an error with the observed state", err)
+	}
+}
+
+func TestBMCAPI_EnsurePower(t *testing.T) {
+	var sets []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") == "power" && req.URL.Query().Get("opt") == "get" {
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		}
+		sets = append(sets, req.URL.RawQuery)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	changed, err := bmc.EnsurePower(0, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed || len(sets) != 0 {
+		t.Errorf("EnsurePower on a powered node changed = %v with requests %v, want no change", changed, sets)
+	}
+
+	changed, err = bmc.EnsurePower(1, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !changed || len(sets) != 1 {
+		t.Errorf("EnsurePower on a powered off node changed = %v with requests %v, want one change", changed, sets)
+	}
+
+	if _, err := bmc.EnsurePower(7, true); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("EnsurePower(7) error = %v, want ErrInvalidNode", err)
+	}
+}
+
+func TestBMCAPI_ToggleNodePower(t *testing.T) {
+	var sets []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") == "power" && req.URL.Query().Get("opt") == "get" {
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		}
+		sets = append(sets, req.URL.RawQuery)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	on, err := bmc.ToggleNodePower(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if on || len(sets) != 1 || sets[0] != "opt=set&type=power&node1=0" {
+		t.Errorf("ToggleNodePower(0) = %v with requests %v, want off with node1=0", on, sets)
+	}
+
+	on, err = bmc.ToggleNodePower(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !on || len(sets) != 2 || sets[1] != "opt=set&type=power&node2=1" {
+		t.Errorf("ToggleNodePower(1) = %v with requests %v, want on with node2=1", on, sets)
+	}
+
+	if _, err := bmc.ToggleNodePower(4); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("ToggleNodePower(4) error = %v, want ErrInvalidNode", err)
+	}
+	if len(sets) != 2 {
+		t.Errorf("ToggleNodePower(4) sent %v", sets[2:])
+	}
+}
+
+func TestBMCAPI_PowerOnSequence(t *testing.T) {
+	var sets []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		sets = append(sets, req.URL.RawQuery)
+		if len(sets) == 3 {
+			return mockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if err := bmc.PowerOnSequence(0, 1, 5); !errors.Is(err, ErrInvalidNode) || len(sets) != 0 {
+		t.Errorf("PowerOnSequence with an invalid node = %v after %d requests, want ErrInvalidNode before any request", err, len(sets))
+	}
+
+	err := bmc.PowerOnSequence(time.Millisecond, 3, 1, 0, 2)
+	if err == nil || !strings.Contains(err.Error(), "node 0") {
+		t.Errorf("PowerOnSequence() error = %v, want failure on node 0", err)
+	}
+	want := []string{"opt=set&type=power&node4=1", "opt=set&type=power&node2=1", "opt=set&type=power&node1=1"}
+	if !reflect.DeepEqual(sets, want) {
+		t.Errorf("requests = %v, want %v", sets, want)
+	}
+}
+
+func TestBMCAPI_AutoPowerOn(t *testing.T) {
+	supported := true
+	var set string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if !supported || query.Get("type") != "auto_power_on" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		if query.Get("opt") == "set" {
+			set = query.Get("node") + "=" + query.Get("enabled")
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"node1":"0","node2":true,"node3":1,"node4":"off"}]}]}`), nil
+	}))
+
+	if result, err := bmc.SetAutoPowerOn(1, true); err != nil || !result.Ok() || set != "1=1" {
+		t.Errorf("SetAutoPowerOn(1, true) = %+v, %v with request %q, want ok with 1=1", result, err, set)
+	}
+	if _, err := bmc.SetAutoPowerOn(0, false); err != nil || set != "0=0" {
+		t.Errorf("SetAutoPowerOn(0, false) error = %v with request %q, want 0=0", err, set)
+	}
+
+	for node, want := range []bool{false, true, true, false} {
+		if enabled, err := bmc.GetAutoPowerOn(node); err != nil || enabled != want {
+			t.Errorf("GetAutoPowerOn(%d) = %v, %v, want %v", node, enabled, err, want)
+		}
+	}
+
+	if _, err := bmc.SetAutoPowerOn(4, true); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("SetAutoPowerOn(4) error = %v, want ErrInvalidNode", err)
+	}
+	if _, err := bmc.GetAutoPowerOn(-1); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("GetAutoPowerOn(-1) error = %v, want ErrInvalidNode", err)
+	}
+
+	supported = false
+	if _, err := bmc.GetAutoPowerOn(0); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetAutoPowerOn() error = %v, want ErrUnsupported", err)
+	}
+	if _, err := bmc.SetAutoPowerOn(0, true); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetAutoPowerOn() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestParsePowerValue(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    bool
+		wantErr bool
+	}{
+		{raw: `"1"`, want: true},
+		{raw: `"0"`, want: false},
+		{raw: `"on"`, want: true},
+		{raw: `"off"`, want: false},
+		{raw: `"ON"`, want: true},
+		{raw: `" Off "`, want: false},
+		{raw: `"true"`, want: true},
+		{raw: `"false"`, want: false},
+		{raw: `true`, want: true},
+		{raw: `false`, want: false},
+		{raw: `1`, want: true},
+		{raw: `0`, want: false},
+		{raw: `"2"`, wantErr: true},
+		{raw: `"standby"`, wantErr: true},
+		{raw: `2`, wantErr: true},
+		{raw: `null`, wantErr: true},
+		{raw: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parsePowerValue(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePowerValue(%s) = %v, want error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePowerValue(%s) unexpected error: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("parsePowerValue(%s) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBMCAPI_GetPower_Normalized(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"node1":"on","node2":false,"node3":1,"node4":"0"}]}]}`), nil
+	}))
+
+	got, err := bmc.GetPower()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"node1": "1", "node2": "0", "node3": "1", "node4": "0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPower() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+package bmcapi
+
+// WithProtectedNodes protects nodes (0-3) from being powered off, e.g. a node that hosts services the BMC
+// or the automation driving it depends on, to prevent an accidental lockout. Powering off a protected node
+// returns ErrProtectedNode, including from EnsurePower, and ShutdownCluster leaves it running; use ForceSetPower to power it off
+// anyway. Powering on and soft resets are not affected. No node is protected by default.
+func WithProtectedNodes(nodes ...int) Option {
+	return func(b *BMCAPI) error {
+		protected := make(map[int]bool, len(nodes))
+		for _, node := range nodes {
+			// Validate node number
+			if !Node(node).Valid() {
+				return ErrInvalidNode
+			}
+			protected[node] = true
+		}
+		b.protectedNodes = protected
+		return nil
+	}
+}
+
+// ForceSetPower is SetPowerResult that also powers off nodes protected with WithProtectedNodes.
+func (b *BMCAPI) ForceSetPower(node, powerState int) (SetResult, error) {
+	return b.setPower(node, powerState, true)
+}
@@ -0,0 +1,38 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_ProtectedNodes(t *testing.T) {
+	var requests int
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+	if err := WithProtectedNodes(0)(bmc); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bmc.SetNodePower(0, PowerOff); !errors.Is(err, ErrProtectedNode) || requests != 0 {
+		t.Errorf("SetNodePower(0, PowerOff) = %v after %d requests, want ErrProtectedNode and no request", err, requests)
+	}
+	if _, err := bmc.SetPowerResult(0, 1); err != nil {
+		t.Errorf("powering on a protected node: %v", err)
+	}
+	if _, err := bmc.SetPowerResult(1, 0); err != nil {
+		t.Errorf("powering off an unprotected node: %v", err)
+	}
+	if _, err := bmc.ForceSetPower(0, 0); err != nil {
+		t.Errorf("ForceSetPower(0, 0) = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("made %d requests, want 3", requests)
+	}
+
+	if err := WithProtectedNodes(4)(bmc); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("WithProtectedNodes(4) error = %v, want ErrInvalidNode", err)
+	}
+}
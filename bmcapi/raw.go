@@ -0,0 +1,45 @@
+package bmcapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RawGet makes an authenticated GET request to endpoint, a path relative to the base URL
+// such as "/api/bmc?opt=get&type=about", and returns the response body without parsing it.
+//
+// RawGet is an escape hatch for firmware endpoints the SDK does not wrap yet. It is an advanced
+// API: the SDK makes no guarantees about the endpoints or the shape of their responses, and
+// callers should switch to a typed method once one exists.
+func (b *BMCAPI) RawGet(endpoint string) ([]byte, error) {
+	if !strings.HasPrefix(endpoint, "/") {
+		return nil, fmt.Errorf("endpoint must start with /")
+	}
+
+	return b.bmcAPICall(endpoint)
+}
+
+// RawSet makes an authenticated opt=set request for the given type with params added to the
+// query string, and returns the response body without parsing it.
+// For example RawSet("reset", url.Values{"node": {"1"}}) calls /api/bmc?opt=set&type=reset&node=1.
+//
+// Like RawGet, RawSet is an unstable escape hatch for endpoints the SDK does not wrap yet.
+func (b *BMCAPI) RawSet(setType string, params url.Values) ([]byte, error) {
+	if setType == "" {
+		return nil, fmt.Errorf("set type must not be empty")
+	}
+
+	endpoint := "/api/bmc?opt=set&type=" + url.QueryEscape(setType)
+	if _, ok := params["opt"]; ok {
+		return nil, fmt.Errorf("params must not contain opt")
+	}
+	if _, ok := params["type"]; ok {
+		return nil, fmt.Errorf("params must not contain type")
+	}
+	if len(params) > 0 {
+		endpoint += "&" + params.Encode()
+	}
+
+	return b.bmcAPICall(endpoint)
+}
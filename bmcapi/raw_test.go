@@ -0,0 +1,33 @@
+package bmcapi
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestBMCAPI_RawSet(t *testing.T) {
+	var gotURL string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		gotURL = req.URL.String()
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	body, err := bmc.RawSet("reset", url.Values{"node": {"1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "http://mock/api/bmc?opt=set&type=reset&node=1"; gotURL != want {
+		t.Errorf("request URL = %q, want %q", gotURL, want)
+	}
+	if string(body) != `{"response":[{"result":"ok"}]}` {
+		t.Errorf("unexpected body %q", body)
+	}
+
+	if _, err := bmc.RawSet("reset", url.Values{"opt": {"get"}}); err == nil {
+		t.Errorf("expected error when params override opt")
+	}
+	if _, err := bmc.RawGet("api/bmc?opt=get&type=about"); err == nil {
+		t.Errorf("expected error for relative endpoint")
+	}
+}
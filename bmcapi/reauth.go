@@ -0,0 +1,119 @@
+package bmcapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReauthInitial  = 500 * time.Millisecond
+	defaultReauthMax      = 30 * time.Second
+	defaultReauthAttempts = 5
+)
+
+// reauthBackoff tracks failed re-authentication attempts, so a flapping BMC is not hammered with
+// authentication requests. The delay is kept across calls and reset by a successful re-authentication.
+type reauthBackoff struct {
+	initial     time.Duration
+	max         time.Duration
+	maxAttempts int
+
+	mu       sync.Mutex
+	failures int
+	next     time.Time
+}
+
+// WithReauthBackoff configures how a bearer session rejected with 401 Unauthorized is re-established,
+// e.g. after the BMC restarted and forgot its tokens. A new token is requested with the stored credentials
+// up to maxAttempts times, waiting initial, then twice as long after every failure up to max, before
+// ErrReauthFailed is returned. The default is 5 attempts starting at 500ms, up to 30s.
+func WithReauthBackoff(initial, max time.Duration, maxAttempts int) Option {
+	return func(b *BMCAPI) error {
+		if initial <= 0 || max < initial {
+			return fmt.Errorf("re-auth backoff must be positive and max at least initial")
+		}
+		if maxAttempts < 1 {
+			return fmt.Errorf("re-auth attempts must be at least 1")
+		}
+		b.reauth = &reauthBackoff{initial: initial, max: max, maxAttempts: maxAttempts}
+		return nil
+	}
+}
+
+// noReauthKey marks a context whose requests must not re-authenticate (see withoutReauth).
+type noReauthKey struct{}
+
+// withoutReauth returns ctx marked so that a rejected session is reported instead of re-established.
+func withoutReauth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noReauthKey{}, true)
+}
+
+// canReauthenticate reports whether a request made under ctx that was rejected with token may request a new token.
+func (b *BMCAPI) canReauthenticate(ctx context.Context) bool {
+	if b.AuthType != "bearer" || ctx.Value(noReauthKey{}) != nil {
+		return false
+	}
+	auth := b.currentAuth()
+	return auth.Password != ""
+}
+
+// reauthBackoff returns the backoff state of b, creating it with the defaults if WithReauthBackoff was not used.
+func (b *BMCAPI) reauthBackoff() *reauthBackoff {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.reauth == nil {
+		b.reauth = &reauthBackoff{initial: defaultReauthInitial, max: defaultReauthMax, maxAttempts: defaultReauthAttempts}
+	}
+	return b.reauth
+}
+
+// reauthenticate requests a new bearer token after token was rejected, backing off between failed attempts.
+// If another request already replaced the rejected token, that token is used without authenticating again.
+func (b *BMCAPI) reauthenticate(ctx context.Context, rejected string) error {
+
+	backoff := b.reauthBackoff()
+
+	// One request re-authenticates at a time; the others wait for its token
+	backoff.mu.Lock()
+	defer backoff.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < backoff.maxAttempts; attempt++ {
+		if b.currentAuth().AccessToken != rejected {
+			return nil
+		}
+
+		if wait := time.Until(backoff.next); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		lastErr = b.authenticateContext(ctx)
+		if lastErr == nil {
+			backoff.failures = 0
+			backoff.next = time.Time{}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delay := backoff.initial << min(backoff.failures, 30)
+		if delay > backoff.max || delay <= 0 {
+			delay = backoff.max
+		}
+		backoff.failures++
+		backoff.next = time.Now().Add(delay)
+	}
+
+	return fmt.Errorf("%w after %d attempts: %w", ErrReauthFailed, backoff.maxAttempts, lastErr)
+
+}
@@ -0,0 +1,104 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newMockBearerBMCAPI(transport http.RoundTripper) *BMCAPI {
+	bmc := newMockBMCAPI(transport)
+	bmc.AuthType = "bearer"
+	bmc.auth.AccessToken = "expired"
+	return bmc
+}
+
+func TestBMCAPI_Reauthenticate(t *testing.T) {
+	authenticated := false
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			authenticated = true
+			return mockResponse(http.StatusOK, `{"id":"fresh"}`), nil
+		}
+		if req.Header.Get("Authorization") != "Bearer fresh" {
+			return mockResponse(http.StatusUnauthorized, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"api":"1.1"}]}]}`), nil
+	}))
+
+	if _, err := bmc.Other(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !authenticated || bmc.currentAuth().AccessToken != "fresh" {
+		t.Errorf("rejected session was not re-established")
+	}
+}
+
+func TestBMCAPI_ReauthenticateBackoff(t *testing.T) {
+	var attempts []time.Time
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			attempts = append(attempts, time.Now())
+			return mockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return mockResponse(http.StatusUnauthorized, ""), nil
+	}))
+	if err := WithReauthBackoff(10*time.Millisecond, 25*time.Millisecond, 4)(bmc); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := bmc.Other()
+	if !errors.Is(err, ErrReauthFailed) {
+		t.Fatalf("Other() error = %v, want ErrReauthFailed", err)
+	}
+	if len(attempts) != 4 {
+		t.Fatalf("authenticated %d times, want 4", len(attempts))
+	}
+	// Delays double from 10ms and are capped at 25ms
+	for i, want := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+		if got := attempts[i+1].Sub(attempts[i]); got < want {
+			t.Errorf("delay before attempt %d = %v, want at least %v", i+2, got, want)
+		}
+	}
+
+	// The backoff carries over to the next call instead of starting over
+	attempts = nil
+	start := time.Now()
+	bmc.Other()
+	if len(attempts) == 0 || attempts[0].Sub(start) < 20*time.Millisecond {
+		t.Errorf("next call re-authenticated without backing off")
+	}
+}
+
+func TestBMCAPI_ReauthenticateCancel(t *testing.T) {
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			return mockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return mockResponse(http.StatusUnauthorized, ""), nil
+	}))
+	if err := WithReauthBackoff(time.Hour, time.Hour, 3)(bmc); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := bmc.bmcAPICallContext(ctx, infoEndpoint); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestBMCAPI_ValidateDoesNotReauthenticate(t *testing.T) {
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			t.Errorf("Validate re-authenticated")
+		}
+		return mockResponse(http.StatusUnauthorized, ""), nil
+	}))
+
+	if ok, err := bmc.Validate(); ok || err != nil {
+		t.Errorf("Validate() = %v, %v, want false, nil", ok, err)
+	}
+}
@@ -0,0 +1,138 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	// bmcDownInitialBackoff is how long WaitForBMC waits after the first failed ping
+	bmcDownInitialBackoff = 500 * time.Millisecond
+
+	// bmcDownMaxBackoff is the longest WaitForBMC waits between pings
+	bmcDownMaxBackoff = 5 * time.Second
+)
+
+// RebootBMC reboots the BMC itself; the nodes keep running. The BMC is unreachable until it has booted
+// again, which takes about a minute. The BMC may drop the connection before answering, which is reported
+// as success because the reboot was already under way.
+func (b *BMCAPI) RebootBMC() (SetResult, error) {
+	return b.restartBMC("reboot", "/api/bmc?opt=set&type=reboot")
+}
+
+// ReloadBMC restarts the BMC daemon without rebooting the BMC, which is much faster than RebootBMC and
+// enough to apply configuration changes. Like RebootBMC, a dropped connection is reported as success.
+// Firmware that cannot reload its daemon returns ErrUnsupported.
+func (b *BMCAPI) ReloadBMC() (SetResult, error) {
+	return b.restartBMC("reload", "/api/bmc?opt=set&type=reload")
+}
+
+// FactoryReset resets the BMC's configuration to factory defaults and reboots it, which also resets its
+// credentials and network settings, so the BMC may come back at another address. It only proceeds when
+// confirm is true, to guard against accidental wipes. Like RebootBMC, a dropped connection is reported as
+// success. Firmware without factory reset support returns ErrUnsupported.
+func (b *BMCAPI) FactoryReset(confirm bool) error {
+	if !confirm {
+		return fmt.Errorf("factory reset not confirmed, pass confirm=true to wipe the BMC configuration")
+	}
+
+	_, err := b.restartBMC("factory reset", "/api/bmc?opt=set&type=factory_reset")
+	return err
+}
+
+// Ping checks that the BMC answers API requests, with a power status query as the lightest read every firmware
+// supports, and returns how long the round trip took. It is never answered from the cache of WithCache.
+func (b *BMCAPI) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	if _, _, err := b.getPowerWithResponse(ctx); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// WaitForBMC pings the BMC until it answers again, e.g. after RebootBMC or a firmware upgrade, and returns how
+// long that took. While the BMC is down, refused or dropped connections, timeouts and the 502, 503 and 504
+// errors of a web server whose backend is not up yet are retried, waiting longer each time up to five seconds.
+// Any other error, such as rejected credentials, is returned right away. It gives up once timeout or ctx expires.
+func (b *BMCAPI) WaitForBMC(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	backoff := bmcDownInitialBackoff
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return time.Since(start), fmt.Errorf("BMC did not answer within %v: %w", timeout, lastErr)
+			}
+			return time.Since(start), ctx.Err()
+		case <-timer.C:
+		}
+
+		_, err := b.Ping(ctx)
+		if err == nil {
+			return time.Since(start), nil
+		}
+		if ctx.Err() == nil && !isBMCDown(err) {
+			return time.Since(start), err
+		}
+		if ctx.Err() == nil {
+			lastErr = err
+		}
+
+		timer.Reset(backoff)
+		backoff = min(backoff*2, bmcDownMaxBackoff)
+	}
+}
+
+// isBMCDown reports whether err is what a BMC that is still booting causes: the request did not get through,
+// or a web server in front of the API answered that it is not available yet.
+func isBMCDown(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// restartBMC is a helper function that requests a restart of the BMC or its daemon at endpoint.
+func (b *BMCAPI) restartBMC(feature, endpoint string) (SetResult, error) {
+
+	bodyBytes, err := b.capabilityAPICall(feature, endpoint)
+	if isDroppedConnection(err) {
+		return SetResult{Raw: "ok"}, nil
+	}
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during %s BMC call: %w", feature, err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+
+}
+
+// isDroppedConnection reports whether err means the BMC closed the connection after receiving a request,
+// as opposed to never being reached.
+func isDroppedConnection(err error) bool {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || isConnectionError(err) {
+		return false
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,108 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_ReloadBMC(t *testing.T) {
+	var response func() (*http.Response, error)
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("opt") != "set" || req.URL.Query().Get("type") != "reload" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return response()
+	}))
+
+	response = func() (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}
+	if result, err := bmc.ReloadBMC(); err != nil || !result.Ok() {
+		t.Errorf("ReloadBMC() = %+v, %v, want ok", result, err)
+	}
+
+	// The daemon may go away before it answers
+	response = func() (*http.Response, error) { return nil, io.EOF }
+	if result, err := bmc.ReloadBMC(); err != nil || !result.Ok() {
+		t.Errorf("ReloadBMC() with dropped connection = %+v, %v, want ok", result, err)
+	}
+
+	response = func() (*http.Response, error) {
+		return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+	}
+	if _, err := bmc.ReloadBMC(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ReloadBMC() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestBMCAPI_FactoryReset(t *testing.T) {
+	var requests int
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if req.URL.Query().Get("type") != "factory_reset" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return nil, io.EOF
+	}))
+
+	if err := bmc.FactoryReset(false); err == nil {
+		t.Errorf("expected error for an unconfirmed factory reset")
+	}
+	if requests != 0 {
+		t.Fatalf("unconfirmed factory reset sent %d requests", requests)
+	}
+
+	if err := bmc.FactoryReset(true); err != nil {
+		t.Errorf("FactoryReset() with dropped connection error = %v, want success", err)
+	}
+}
+
+func TestBMCAPI_WaitForBMC(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	var responses []func() (*http.Response, error)
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "power" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		next := responses[0]
+		if len(responses) > 1 {
+			responses = responses[1:]
+		}
+		return next()
+	}))
+
+	// The BMC refuses connections while it reboots, then its web server answers before the API is up
+	responses = []func() (*http.Response, error){
+		func() (*http.Response, error) { return nil, refused },
+		func() (*http.Response, error) { return mockResponse(http.StatusServiceUnavailable, ""), nil },
+		func() (*http.Response, error) { return mockResponse(http.StatusOK, mockPowerResponse), nil },
+	}
+	elapsed, err := bmc.WaitForBMC(context.Background(), 10*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed < bmcDownInitialBackoff {
+		t.Errorf("WaitForBMC() = %v, want at least the backoff after the first failure", elapsed)
+	}
+
+	responses = []func() (*http.Response, error){
+		func() (*http.Response, error) { return mockResponse(http.StatusUnauthorized, ""), nil },
+	}
+	var httpErr *HTTPError
+	if _, err := bmc.WaitForBMC(context.Background(), 10*time.Second); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("WaitForBMC() with rejected credentials error = %v, want the 401 right away", err)
+	}
+
+	responses = []func() (*http.Response, error){
+		func() (*http.Response, error) { return nil, refused },
+	}
+	if _, err := bmc.WaitForBMC(context.Background(), 50*time.Millisecond); err == nil || !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Errorf("WaitForBMC() on a BMC that stays down error = %v, want the last connection error", err)
+	}
+}
@@ -0,0 +1,61 @@
+package bmcapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxRedirects is how many redirects a single request follows before giving up.
+const maxRedirects = 10
+
+// isRedirect reports whether status is a redirect that carries a Location.
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// doFollowingRedirects sends req with b.Client and follows redirects itself instead of leaving them to the client.
+//
+// The http.Client turns a redirected GET with a body, such as the bearer authentication request, into one
+// without it, so a BMC that redirects http to https would reject the credentials. Here every redirect is
+// followed with the original method, headers and body. Redirects are only followed to the same host, and never
+// from https to http, as following them would send the credentials elsewhere or in plaintext; such a redirect
+// is returned as an error.
+// The CheckRedirect function of b.Client is not used.
+func (b *BMCAPI) doFollowingRedirects(req *http.Request) (*http.Response, error) {
+	client := *b.Client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	for redirects := 0; ; redirects++ {
+		resp, err := client.Do(req)
+		if err != nil || !isRedirect(resp.StatusCode) {
+			return resp, err
+		}
+
+		location, err := resp.Location()
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid redirect from BMC: %w", err)
+		}
+		if redirects == maxRedirects {
+			return nil, fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if location.Hostname() != req.URL.Hostname() {
+			return nil, fmt.Errorf("BMC redirected to another host (%s), not following it with credentials", location.Host)
+		}
+		if req.URL.Scheme == "https" && location.Scheme != "https" {
+			return nil, fmt.Errorf("BMC redirected from https to %s, not following it with credentials", location.Scheme)
+		}
+
+		if req, err = cloneRequestTo(req, location.String()); err != nil {
+			return nil, fmt.Errorf("cannot follow redirect to %s: %w", redactURL(location), err)
+		}
+	}
+}
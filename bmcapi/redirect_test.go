@@ -0,0 +1,69 @@
+package bmcapi
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBMCAPI_FollowsRedirectWithBody(t *testing.T) {
+	client := &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Scheme == "http" {
+			resp := mockResponse(http.StatusMovedPermanently, "")
+			resp.Header.Set("Location", "https://mock"+req.URL.RequestURI())
+			return resp, nil
+		}
+		if req.URL.Path == "/api/bmc/authenticate" {
+			body, _ := io.ReadAll(req.Body)
+			if !strings.Contains(string(body), `"password":"pass"`) {
+				t.Errorf("redirected authentication request lost its body: %q", body)
+			}
+			return mockResponse(http.StatusOK, `{"id":"token-123"}`), nil
+		}
+		if req.Header.Get("Authorization") != "Bearer token-123" {
+			t.Errorf("redirected request lost its Authorization header")
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	})}
+
+	bmc, err := NewBMCAPI("http://mock", "bearer", "user", "pass", client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bmc.USBBootResult(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBMCAPI_RefusesRedirectToOtherHost(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "mock" {
+			t.Errorf("followed redirect to %s", req.URL.Host)
+		}
+		resp := mockResponse(http.StatusFound, "")
+		resp.Header.Set("Location", "https://elsewhere.example/login")
+		return resp, nil
+	}))
+
+	if _, err := bmc.USBBootResult(0); err == nil || !strings.Contains(err.Error(), "another host") {
+		t.Errorf("USBBootResult() error = %v, want a redirect to another host error", err)
+	}
+}
+
+func TestBMCAPI_RefusesRedirectToHTTP(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Scheme != "https" {
+			t.Errorf("followed redirect to %s with %q", req.URL, req.Header.Get("Authorization"))
+		}
+		resp := mockResponse(http.StatusFound, "")
+		resp.Header.Set("Location", "http://mock/api/bmc?opt=set&type=usb_boot&node=0")
+		return resp, nil
+	}))
+	bmc.BaseURL = "https://mock"
+	bmc.primaryURL = "https://mock"
+
+	if _, err := bmc.USBBootResult(0); err == nil || !strings.Contains(err.Error(), "from https to http") {
+		t.Errorf("USBBootResult() error = %v, want a redirect from https to http error", err)
+	}
+}
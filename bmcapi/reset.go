@@ -0,0 +1,63 @@
+package bmcapi
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// defaultPowerCycleDelay is how long a hard reset keeps a node powered off
+const defaultPowerCycleDelay = 2 * time.Second
+
+// ResetType selects how ResetNodeWithType resets a node.
+type ResetType int
+
+const (
+	// ResetSoft pulses the node's reset line with the firmware's reset call, as ResetNodeResult does.
+	// It is enough for a hung operating system.
+	ResetSoft ResetType = iota
+
+	// ResetHard power cycles the node: it is powered off, kept off briefly and powered on again.
+	// Use it for a crashed module that no longer responds to its reset line.
+	ResetHard
+)
+
+// String returns "soft" or "hard", or ResetType(n) for an invalid type.
+func (t ResetType) String() string {
+	switch t {
+	case ResetSoft:
+		return "soft"
+	case ResetHard:
+		return "hard"
+	}
+	return "ResetType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// ResetNodeWithType resets the specified node (0-3) the way resetType selects. A hard reset powers the node
+// off, which is refused for nodes protected with WithProtectedNodes, and then on even if it was off before.
+func (b *BMCAPI) ResetNodeWithType(node int, resetType ResetType) (SetResult, error) {
+	switch resetType {
+	case ResetSoft:
+		return b.ResetNodeResult(node)
+	case ResetHard:
+	default:
+		return SetResult{}, fmt.Errorf("invalid reset type %v", resetType)
+	}
+
+	if _, err := b.SetPowerResult(node, 0); err != nil {
+		return SetResult{}, fmt.Errorf("hard reset of node %d: %w", node, err)
+	}
+
+	delay := b.powerCycleDelay
+	if delay <= 0 {
+		delay = defaultPowerCycleDelay
+	}
+	time.Sleep(delay)
+
+	result, err := b.SetPowerResult(node, 1)
+	if err != nil {
+		return SetResult{}, fmt.Errorf("hard reset of node %d left it powered off: %w", node, err)
+	}
+
+	return result, nil
+}
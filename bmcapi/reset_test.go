@@ -0,0 +1,46 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_ResetNodeWithType(t *testing.T) {
+	var requests []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.URL.RawQuery)
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+	bmc.powerCycleDelay = time.Millisecond
+
+	if _, err := bmc.ResetNodeWithType(1, ResetSoft); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"opt=set&type=reset&node=1"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("soft reset requests = %v, want %v", requests, want)
+	}
+
+	requests = nil
+	if result, err := bmc.ResetNodeWithType(1, ResetHard); err != nil || !result.Ok() {
+		t.Fatalf("ResetNodeWithType(1, ResetHard) = %+v, %v", result, err)
+	}
+	if want := []string{"opt=set&type=power&node2=0", "opt=set&type=power&node2=1"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("hard reset requests = %v, want %v", requests, want)
+	}
+
+	if err := WithProtectedNodes(1)(bmc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bmc.ResetNodeWithType(1, ResetHard); !errors.Is(err, ErrProtectedNode) {
+		t.Errorf("hard reset of a protected node error = %v, want ErrProtectedNode", err)
+	}
+	if _, err := bmc.ResetNodeWithType(1, ResetType(7)); err == nil {
+		t.Errorf("expected error for an invalid reset type")
+	}
+	if ResetHard.String() != "hard" {
+		t.Errorf("ResetHard.String() = %q", ResetHard.String())
+	}
+}
@@ -0,0 +1,92 @@
+package bmcapi
+
+import "fmt"
+
+// defaultSDCardThreshold is the usage in percent above which CheckSDCardUsage warns when WithSDCardThreshold is not given.
+const defaultSDCardThreshold = 90
+
+// SDCardInfo is the capacity of the microSD card in the BMC's slot.
+type SDCardInfo struct {
+	TotalBytes uint64
+	FreeBytes  uint64
+	UsedBytes  uint64
+}
+
+// SDCardInfo returns the capacity and usage of the microSD card in the BMC's slot.
+// Firmware that does not report the SD card returns ErrUnsupported.
+func (b *BMCAPI) SDCardInfo() (*SDCardInfo, error) {
+	bodyBytes, err := b.capabilityAPICall("SD card", "/api/bmc?opt=get&type=sdcard")
+	if err != nil {
+		return nil, fmt.Errorf("error during SD Card call: %w", err)
+	}
+
+	// The card is reported as {"response":[{"result":[{"total":<bytes>,"free":<bytes>,"use":<bytes>}] }]}
+	// where numbers may also be sent as strings
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SD card response: %w", err)
+	}
+
+	var info SDCardInfo
+	for key, field := range map[string]*uint64{"total": &info.TotalBytes, "free": &info.FreeBytes, "use": &info.UsedBytes} {
+		raw, ok := result[key]
+		if !ok {
+			continue
+		}
+		value, err := parseNumber(raw)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid SD card %s %s", key, raw)
+		}
+		*field = uint64(value)
+	}
+	if _, ok := result["use"]; !ok && info.TotalBytes >= info.FreeBytes {
+		info.UsedBytes = info.TotalBytes - info.FreeBytes
+	}
+
+	return &info, nil
+}
+
+// SDCardUsagePercent returns how full the BMC's microSD card is, from 0 to 100.
+func (b *BMCAPI) SDCardUsagePercent() (float64, error) {
+	info, err := b.SDCardInfo()
+	if err != nil {
+		return 0, err
+	}
+	if info.TotalBytes == 0 {
+		return 0, fmt.Errorf("BMC reports no SD card capacity, is a card inserted?")
+	}
+
+	return float64(info.UsedBytes) * 100 / float64(info.TotalBytes), nil
+}
+
+// WithSDCardThreshold sets the usage of the BMC's microSD card in percent above which CheckSDCardUsage
+// returns an *SDCardUsageError. The default is 90.
+func WithSDCardThreshold(percent float64) Option {
+	return func(b *BMCAPI) error {
+		if percent <= 0 || percent > 100 {
+			return fmt.Errorf("SD card threshold must be above 0 and at most 100")
+		}
+		b.sdCardThreshold = percent
+		return nil
+	}
+}
+
+// CheckSDCardUsage returns an *SDCardUsageError if the BMC's microSD card is fuller than the threshold set
+// with WithSDCardThreshold, e.g. as a check before a flash that stores the image on the card, so it does not
+// fail halfway for lack of space. Errors reading the card are returned as they are.
+func (b *BMCAPI) CheckSDCardUsage() error {
+	usage, err := b.SDCardUsagePercent()
+	if err != nil {
+		return err
+	}
+
+	threshold := b.sdCardThreshold
+	if threshold <= 0 {
+		threshold = defaultSDCardThreshold
+	}
+	if usage > threshold {
+		return &SDCardUsageError{UsagePercent: usage, ThresholdPercent: threshold}
+	}
+
+	return nil
+}
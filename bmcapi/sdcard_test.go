@@ -0,0 +1,62 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_SDCard(t *testing.T) {
+	body := `{"response":[{"result":[{"total":1000,"free":"250","use":750}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "sdcard" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	info, err := bmc.SDCardInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *info != (SDCardInfo{TotalBytes: 1000, FreeBytes: 250, UsedBytes: 750}) {
+		t.Errorf("SDCardInfo() = %+v", *info)
+	}
+	if usage, err := bmc.SDCardUsagePercent(); err != nil || usage != 75 {
+		t.Errorf("SDCardUsagePercent() = %v, %v, want 75", usage, err)
+	}
+
+	// Below the default threshold of 90%
+	if err := bmc.CheckSDCardUsage(); err != nil {
+		t.Errorf("CheckSDCardUsage() error = %v, want nil", err)
+	}
+	if err := WithSDCardThreshold(70)(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var usageErr *SDCardUsageError
+	if err := bmc.CheckSDCardUsage(); !errors.As(err, &usageErr) || usageErr.UsagePercent != 75 || usageErr.ThresholdPercent != 70 {
+		t.Errorf("CheckSDCardUsage() error = %v, want an SDCardUsageError", err)
+	}
+
+	// Usage is derived from the free space if the firmware leaves it out
+	body = `{"response":[{"result":[{"total":1000,"free":100}]}]}`
+	if usage, err := bmc.SDCardUsagePercent(); err != nil || usage != 90 {
+		t.Errorf("SDCardUsagePercent() without use = %v, %v, want 90", usage, err)
+	}
+
+	body = `{"response":[{"result":[{"total":0,"free":0,"use":0}]}]}`
+	if _, err := bmc.SDCardUsagePercent(); err == nil {
+		t.Errorf("expected error without an SD card")
+	}
+
+	if err := WithSDCardThreshold(0)(bmc); err == nil {
+		t.Errorf("expected error for a zero threshold")
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusNotFound, ""), nil
+	}))
+	if err := unsupported.CheckSDCardUsage(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("CheckSDCardUsage() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,80 @@
+package bmcapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// SessionData is a bearer session exported by ExportSession, so it can be stored, e.g. on disk by a CLI,
+// and reused with NewBMCAPIFromSession without authenticating again. It holds the bearer token but never
+// the password; treat it as a credential all the same. Name and Description are the token's label (see TokenInfo).
+type SessionData struct {
+	BaseURL     string `json:"base_url"`
+	APIPrefix   string `json:"api_prefix,omitempty"`
+	Username    string `json:"username,omitempty"`
+	AccessToken string `json:"token"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ExportSession returns the current bearer session of b. Basic auth has no session to export, as every
+// request carries the password, and with WithLazyAuth there is no session before the first call.
+func (b *BMCAPI) ExportSession() (SessionData, error) {
+
+	if b.AuthType != "bearer" {
+		return SessionData{}, fmt.Errorf("only bearer sessions can be exported, auth type is %s", b.AuthType)
+	}
+
+	auth := b.currentAuth()
+	if auth.AccessToken == "" {
+		return SessionData{}, fmt.Errorf("no session to export, not authenticated yet")
+	}
+
+	session := SessionData{
+		BaseURL:     b.baseURL(),
+		Username:    auth.Username,
+		AccessToken: auth.AccessToken,
+		Name:        auth.Name,
+		Description: auth.Description,
+	}
+	if b.customAPIPrefix {
+		session.APIPrefix = b.apiPrefix
+	}
+
+	return session, nil
+}
+
+// NewBMCAPIFromSession creates a BMCAPI that reuses a session exported by ExportSession, without contacting
+// the BMC. client and opts are used as in NewBMCAPI. As the password is not part of the session, a session
+// the BMC no longer accepts cannot be re-established; requests then fail with 401 Unauthorized, and a new
+// session has to be created with NewBMCAPI.
+func NewBMCAPIFromSession(session SessionData, client *http.Client, opts ...Option) (*BMCAPI, error) {
+
+	if session.AccessToken == "" {
+		return nil, fmt.Errorf("session has no token")
+	}
+
+	sessionOpts := []Option{WithBearerToken(session.AccessToken)}
+	if session.APIPrefix != "" {
+		sessionOpts = append(sessionOpts, WithAPIPrefix(session.APIPrefix))
+	}
+
+	b, err := NewBMCAPI(session.BaseURL, "bearer", session.Username, "", client, append(sessionOpts, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := *b.currentAuth()
+	auth.Name = session.Name
+	auth.Description = session.Description
+	b.setAuth(&auth)
+
+	return b, nil
+}
+
+// TokenInfo returns the name and description the BMC gave the current bearer token when it was issued,
+// e.g. to tell tokens apart when several exist. Both are empty for basic auth and before authenticating.
+func (b *BMCAPI) TokenInfo() (name, description string) {
+	auth := b.currentAuth()
+	return auth.Name, auth.Description
+}
@@ -0,0 +1,76 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBMCAPI_ExportSession(t *testing.T) {
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"api":"1.1"}]}]}`), nil
+	}))
+	bmc.auth.AccessToken = "token"
+	bmc.auth.Name, bmc.auth.Description = "ci", "token for CI"
+	bmc.apiPrefix, bmc.customAPIPrefix = "/tpi/api/bmc", true
+
+	session, err := bmc.ExportSession()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, _ := json.Marshal(session)
+	if strings.Contains(string(encoded), "pass") {
+		t.Errorf("exported session contains the password: %s", encoded)
+	}
+
+	var decoded SessionData
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	var got *http.Request
+	restored, err := NewBMCAPIFromSession(decoded, &http.Client{Transport: mockTransport(func(req *http.Request) (*http.Response, error) {
+		got = req
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"api":"1.1"}]}]}`), nil
+	})})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != nil {
+		t.Errorf("restoring a session contacted the BMC")
+	}
+	if name, description := restored.TokenInfo(); name != "ci" || description != "token for CI" {
+		t.Errorf("TokenInfo() = %q, %q after restoring the session", name, description)
+	}
+	if _, err := restored.Other(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Header.Get("Authorization") != "Bearer token" || got.URL.String() != "http://mock/tpi/api/bmc?opt=get&type=other" {
+		t.Errorf("restored session sent %s with %q", got.URL, got.Header.Get("Authorization"))
+	}
+
+	basic := newMockBMCAPI(nil)
+	if _, err := basic.ExportSession(); err == nil {
+		t.Errorf("expected error exporting a basic auth session")
+	}
+	if _, err := NewBMCAPIFromSession(SessionData{BaseURL: "http://mock"}, nil); err == nil {
+		t.Errorf("expected error for a session without token")
+	}
+}
+
+func TestBMCAPI_TokenInfo(t *testing.T) {
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusOK, `{"id":"fresh","name":"tpi","description":"created by tpi"}`), nil
+	}))
+
+	if name, description := bmc.TokenInfo(); name != "" || description != "" {
+		t.Errorf("TokenInfo() before authenticating = %q, %q", name, description)
+	}
+	if err := bmc.Authenticate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name, description := bmc.TokenInfo(); name != "tpi" || description != "created by tpi" {
+		t.Errorf("TokenInfo() = %q, %q", name, description)
+	}
+}
@@ -0,0 +1,179 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// shutdownCommand is the command ShutdownCluster sends over the serial console of each running node
+	shutdownCommand = "poweroff"
+
+	// powerDownMessage is what the Linux kernel prints on the console right before it powers down
+	powerDownMessage = "reboot: Power down"
+
+	// defaultShutdownTimeout is how long ShutdownCluster waits for a node to shut down before cutting its power
+	defaultShutdownTimeout = 2 * time.Minute
+)
+
+// ShutdownCluster powers off every node that is on.
+//
+// When graceful is true, "poweroff" is first sent over the serial console of each running node, and
+// ShutdownCluster waits up to two minutes per node for the kernel to print "reboot: Power down" before
+// cutting its power. The BMC keeps reporting a node as on until its power is cut, so the console is the
+// only sign of a completed shutdown. Nodes that did not confirm in time are powered off anyway and
+// reported in a *ShutdownError. When graceful is false, the nodes are powered off right away.
+// Nodes protected with WithProtectedNodes are left running and reported with ErrProtectedNode.
+func (b *BMCAPI) ShutdownCluster(graceful bool) error {
+	running, errs, err := b.runningNodes()
+	if err != nil {
+		return err
+	}
+
+	var notGraceful []int
+	if graceful {
+		notGraceful = b.shutdownNodes(running)
+	}
+
+	for _, node := range running {
+		if _, err := b.SetPowerResult(node, 0); err != nil {
+			errs = append(errs, fmt.Errorf("powering off node %d: %w", node, err))
+		}
+	}
+	if len(notGraceful) > 0 {
+		errs = append(errs, &ShutdownError{Nodes: notGraceful})
+	}
+
+	return errors.Join(errs...)
+}
+
+// ShutdownOrder powers off the running nodes one at a time in the given order, like ShutdownCluster, so nodes
+// others depend on go last, e.g. the node serving NFS storage to the workers. Each node is shut down and powered
+// off before the next one is started on. The order must include every node that is on; it may include nodes that
+// are off, which are skipped. It stops at the first node that could not be powered off, leaving the nodes after it
+// running, and reports nodes that did not shut down gracefully in a *ShutdownError as ShutdownCluster does.
+func (b *BMCAPI) ShutdownOrder(order []int, graceful bool) error {
+	seen := make(map[int]bool, len(order))
+	for _, node := range order {
+		// Validate node number
+		if !Node(node).Valid() {
+			return fmt.Errorf("invalid node %d in shutdown order: %w", node, ErrInvalidNode)
+		}
+		if seen[node] {
+			return fmt.Errorf("node %d appears more than once in shutdown order", node)
+		}
+		seen[node] = true
+	}
+
+	running, errs, err := b.runningNodes()
+	if err != nil {
+		return err
+	}
+	var missing []int
+	for _, node := range running {
+		if !seen[node] {
+			missing = append(missing, node)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("shutdown order does not include running nodes %v", missing)
+	}
+
+	var notGraceful []int
+	for _, node := range order {
+		if !slices.Contains(running, node) {
+			continue
+		}
+		if graceful {
+			notGraceful = append(notGraceful, b.shutdownNodes([]int{node})...)
+		}
+		if _, err := b.SetPowerResult(node, 0); err != nil {
+			errs = append(errs, fmt.Errorf("powering off node %d: %w", node, err))
+			break
+		}
+	}
+	if len(notGraceful) > 0 {
+		errs = append(errs, &ShutdownError{Nodes: notGraceful})
+	}
+
+	return errors.Join(errs...)
+}
+
+// runningNodes is a helper function for the shutdown methods that returns the nodes that are on and not protected
+// with WithProtectedNodes, together with an ErrProtectedNode error for each protected node that is on.
+func (b *BMCAPI) runningNodes() ([]int, []error, error) {
+	power, err := b.GetPower()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var running []int
+	var errs []error
+	for node := 0; node < 4; node++ {
+		on, err := nodePowerState(power, node)
+		if err != nil {
+			return nil, nil, err
+		}
+		if on && b.protectedNodes[node] {
+			errs = append(errs, fmt.Errorf("powering off node %d: %w", node, ErrProtectedNode))
+		} else if on {
+			running = append(running, node)
+		}
+	}
+
+	return running, errs, nil
+}
+
+// shutdownNodes is a helper function that asks the operating system of each node to shut down and waits,
+// concurrently, for each to confirm on its console. It returns the nodes that did not confirm in time.
+func (b *BMCAPI) shutdownNodes(nodes []int) []int {
+	timeout := b.shutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed []int
+	)
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.shutdownNode(node, timeout); err != nil {
+				mu.Lock()
+				failed = append(failed, node)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(failed)
+	return failed
+}
+
+// shutdownNode is a helper function that sends the shutdown command to node and waits for its power down message.
+func (b *BMCAPI) shutdownNode(node int, timeout time.Duration) error {
+	var cursor consoleCursor
+	if _, err := b.readConsole(context.Background(), node, &cursor); err != nil {
+		return err
+	}
+	if _, err := b.SetUARTResult(node, shutdownCommand); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err := b.waitForConsole(ctx, node, &cursor, func(console string) bool {
+		return strings.Contains(console, powerDownMessage)
+	})
+	return err
+}
@@ -0,0 +1,108 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_ShutdownCluster(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		consoles = map[string]string{"0": "login: ", "2": "login: "}
+		offs     []string
+	)
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		query := req.URL.Query()
+		switch {
+		case query.Get("type") == "power" && query.Get("opt") == "get":
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		case query.Get("type") == "uart" && query.Get("opt") == "get":
+			return mockResponse(http.StatusOK, uartResponse(consoles[query.Get("node")])), nil
+		case query.Get("type") == "uart":
+			// Only node 0 shuts down; node 2 ignores the command
+			if node := query.Get("node"); node == "0" && query.Get("cmd") == "poweroff" {
+				consoles[node] += "poweroff\n[  42.000000] reboot: Power down\n"
+			}
+		default:
+			offs = append(offs, req.URL.RawQuery)
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+	bmc.shutdownTimeout = 50 * time.Millisecond
+
+	err := bmc.ShutdownCluster(true)
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) || !slices.Equal(shutdownErr.Nodes, []int{2}) {
+		t.Errorf("ShutdownCluster() error = %v, want a ShutdownError for node 2", err)
+	}
+	// mockPowerResponse has nodes 0 and 2 on, and both must be powered off
+	if len(offs) != 2 {
+		t.Errorf("power requests = %v, want one for each running node", offs)
+	}
+
+	offs = nil
+	if err := bmc.ShutdownCluster(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(offs) != 2 {
+		t.Errorf("power requests = %v, want one for each running node", offs)
+	}
+
+	// A protected node is neither shut down nor powered off
+	if err := WithProtectedNodes(2)(bmc); err != nil {
+		t.Fatal(err)
+	}
+	offs = nil
+	if err := bmc.ShutdownCluster(false); !errors.Is(err, ErrProtectedNode) {
+		t.Errorf("ShutdownCluster() error = %v, want ErrProtectedNode", err)
+	}
+	if len(offs) != 1 {
+		t.Errorf("power requests = %v, want one for the unprotected node", offs)
+	}
+}
+
+func TestBMCAPI_ShutdownOrder(t *testing.T) {
+	var requests []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		switch {
+		case query.Get("type") == "power" && query.Get("opt") == "get":
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		case query.Get("type") == "uart" && query.Get("opt") == "get":
+			console := ""
+			if slices.Contains(requests, "uart "+query.Get("node")) {
+				console = "poweroff\n[  42.000000] reboot: Power down\n"
+			}
+			return mockResponse(http.StatusOK, uartResponse(console)), nil
+		case query.Get("type") == "uart":
+			requests = append(requests, "uart "+query.Get("node"))
+		default:
+			requests = append(requests, req.URL.RawQuery)
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	// mockPowerResponse has nodes 0 and 2 on; node 0 is the storage node and goes last
+	if err := bmc.ShutdownOrder([]int{2, 1, 0}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"uart 2", "opt=set&type=power&node3=0", "uart 0", "opt=set&type=power&node1=0"}
+	if !slices.Equal(requests, want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+
+	requests = nil
+	if err := bmc.ShutdownOrder([]int{2, 1}, false); err == nil || len(requests) != 0 {
+		t.Errorf("ShutdownOrder() without running node 0 = %v after %v, want an error before any request", err, requests)
+	}
+	if err := bmc.ShutdownOrder([]int{2, 2, 0}, false); err == nil {
+		t.Errorf("expected error for a node given twice")
+	}
+}
@@ -0,0 +1,77 @@
+package bmcapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// flightGroup combines identical read requests made at the same time, so a slow BMC polled from many goroutines
+// answers each read once. A read started while the same read is in flight waits for its result instead of
+// sending a request of its own. Writes are never combined, as each of them must reach the BMC, and neither are
+// reads under a context with a deadline, which must not be cut short or extended by another caller's deadline.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is a read in flight and, once done is closed, its result.
+type flightCall struct {
+	done chan struct{}
+
+	body     []byte
+	resp     *http.Response
+	respBody []byte
+	err      error
+}
+
+// do returns the result of fn for key, calling it only if no call for key is in flight. fn runs under ctx
+// without its cancellation, as other callers may be waiting for it; each caller stops waiting when its ctx is done.
+// ctx must not have a deadline, as it would be lost (see flightGroup).
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) ([]byte, *http.Response, error)) ([]byte, *http.Response, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(context.WithoutCancel(ctx), key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result()
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// run calls fn for call and publishes its result.
+func (g *flightGroup) run(ctx context.Context, key string, call *flightCall, fn func(context.Context) ([]byte, *http.Response, error)) {
+	call.body, call.resp, call.err = fn(ctx)
+	if call.resp != nil && call.resp.Body != nil {
+		call.respBody, _ = io.ReadAll(call.resp.Body)
+	}
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+}
+
+// result returns a copy of the result of call for one caller, so callers cannot affect each other.
+func (c *flightCall) result() ([]byte, *http.Response, error) {
+	if c.resp == nil {
+		return bytes.Clone(c.body), nil, c.err
+	}
+
+	resp := *c.resp
+	resp.Header = c.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(c.respBody))
+	return bytes.Clone(c.body), &resp, c.err
+}
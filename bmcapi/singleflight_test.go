@@ -0,0 +1,180 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_ConcurrentReadsShareRequest(t *testing.T) {
+	const callers = 10
+	var requests atomic.Int32
+	release := make(chan struct{})
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("opt") == "set" {
+			requests.Add(100)
+			return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+		}
+		requests.Add(1)
+		<-release
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	var wg sync.WaitGroup
+	results := make(chan map[string]string, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			power, err := bmc.GetPower()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results <- power
+		}()
+	}
+
+	// Hold the request until the other callers had time to join it
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("%d concurrent GetPower calls made %d requests, want 1", callers, got)
+	}
+	for power := range results {
+		if power["node1"] != "1" {
+			t.Errorf("GetPower() = %v, want the shared result", power)
+		}
+	}
+
+	// Writes are never combined
+	requests.Store(0)
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bmc.SetPowerResult(0, 1)
+		}()
+	}
+	wg.Wait()
+	if got := requests.Load(); got != 200 {
+		t.Errorf("two concurrent SetPowerResult calls made %d requests, want 2", got/100)
+	}
+}
+
+func TestBMCAPI_ConcurrentReadsCancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		<-release
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	// A caller that gives up does not wait for the shared request
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := bmc.isNodeOn(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("isNodeOn() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestBMCAPI_ConcurrentReadsDeadline(t *testing.T) {
+	var requests atomic.Int32
+	var deadlines []time.Time
+	var mu sync.Mutex
+	release := make(chan struct{})
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		requests.Add(1)
+		deadline, _ := req.Context().Deadline()
+		mu.Lock()
+		deadlines = append(deadlines, deadline)
+		mu.Unlock()
+		<-release
+		return mockResponse(http.StatusOK, mockPowerResponse), nil
+	}))
+
+	// Reads with their own deadline keep it, rather than sharing a request under the default timeout
+	want := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := bmc.isNodeOn(ctx, 0); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	for requests.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for _, deadline := range deadlines {
+		if !deadline.Equal(want) {
+			t.Errorf("request deadline = %v, want the caller's %v", deadline, want)
+		}
+	}
+}
+
+func TestBMCAPI_ValidateDoesNotShareRequest(t *testing.T) {
+	var expired atomic.Int32
+	second := make(chan struct{})
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			return mockResponse(http.StatusOK, `{"id":"fresh"}`), nil
+		}
+		if req.Header.Get("Authorization") != "Bearer fresh" {
+			// Hold the first read until a second one was sent, or it is clear none will be
+			switch expired.Add(1) {
+			case 1:
+				select {
+				case <-second:
+				case <-time.After(500 * time.Millisecond):
+				}
+			case 2:
+				close(second)
+			}
+			return mockResponse(http.StatusUnauthorized, ""), nil
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":[{"api":"1.1"}]}]}`), nil
+	}))
+
+	var wg sync.WaitGroup
+	var readErr error
+	var valid bool
+	var validateErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, readErr = bmc.bmcAPICallContext(context.Background(), infoEndpoint)
+	}()
+	go func() {
+		defer wg.Done()
+		valid, validateErr = bmc.Validate()
+	}()
+	wg.Wait()
+
+	if readErr != nil {
+		t.Errorf("read alongside Validate error = %v, want it re-authenticated", readErr)
+	}
+	if valid || validateErr != nil {
+		t.Errorf("Validate() alongside a read = %v, %v, want false, nil", valid, validateErr)
+	}
+	if got := expired.Load(); got != 2 {
+		t.Errorf("made %d requests with the expired token, want one each for Validate and the read", got)
+	}
+}
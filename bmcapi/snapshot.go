@@ -0,0 +1,49 @@
+package bmcapi
+
+import (
+	"errors"
+	"sync"
+)
+
+// Snapshot is the state of the BMC and its nodes as read by a single call to Snapshot.
+// Each part is fetched independently: when one fails, its field is left empty and the
+// matching error field says why, while the other parts are still filled in.
+type Snapshot struct {
+	Other    *bmcOther
+	OtherErr error
+
+	Power    map[string]string
+	PowerErr error
+
+	Nodes    []NodeInfo
+	NodesErr error
+}
+
+// Snapshot reads Other, GetPower and NodeInfo concurrently and returns them together.
+// An error is only returned when every part failed, e.g. because the BMC is unreachable;
+// otherwise check the error fields of the Snapshot for partial failures.
+func (b *BMCAPI) Snapshot() (*Snapshot, error) {
+	var snapshot Snapshot
+	var wg sync.WaitGroup
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		snapshot.Other, snapshot.OtherErr = b.Other()
+	}()
+	go func() {
+		defer wg.Done()
+		snapshot.Power, snapshot.PowerErr = b.GetPower()
+	}()
+	go func() {
+		defer wg.Done()
+		snapshot.Nodes, snapshot.NodesErr = b.NodeInfo()
+	}()
+	wg.Wait()
+
+	if snapshot.OtherErr != nil && snapshot.PowerErr != nil && snapshot.NodesErr != nil {
+		return nil, errors.Join(snapshot.OtherErr, snapshot.PowerErr, snapshot.NodesErr)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,41 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBMCAPI_Snapshot(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Query().Get("type") {
+		case "other":
+			return mockResponse(http.StatusOK, `{"response":[{"result":[{"api":"1.1","version":"2.3.4"}]}]}`), nil
+		case "power":
+			return mockResponse(http.StatusOK, mockPowerResponse), nil
+		default:
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+	}))
+
+	snapshot, err := bmc.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.OtherErr != nil || snapshot.Other.Version != "2.3.4" {
+		t.Errorf("Other = %+v, %v", snapshot.Other, snapshot.OtherErr)
+	}
+	if snapshot.PowerErr != nil || len(snapshot.Power) != 4 {
+		t.Errorf("Power = %v, %v", snapshot.Power, snapshot.PowerErr)
+	}
+	if !errors.Is(snapshot.NodesErr, ErrUnsupported) || snapshot.Nodes != nil {
+		t.Errorf("Nodes = %v, %v, want ErrUnsupported", snapshot.Nodes, snapshot.NodesErr)
+	}
+
+	down := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusInternalServerError, ""), nil
+	}))
+	if _, err := down.Snapshot(); err == nil {
+		t.Errorf("expected error when every part fails")
+	}
+}
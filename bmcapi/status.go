@@ -0,0 +1,113 @@
+package bmcapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BMCStatus describes the load on the BMC itself. Fields the firmware does not report are zero.
+type BMCStatus struct {
+	Uptime time.Duration
+	// Load holds the 1, 5 and 15 minute load averages
+	Load          [3]float64
+	MemTotalBytes uint64
+	MemFreeBytes  uint64
+}
+
+// BMCStatus returns the uptime, load average and memory usage of the BMC, e.g. to tell whether
+// a sluggish BMC is overloaded before starting a flash. Firmware that does not report its status
+// returns ErrUnsupported.
+func (b *BMCAPI) BMCStatus() (*BMCStatus, error) {
+	bodyBytes, err := b.capabilityAPICall("BMC status", "/api/bmc?opt=get&type=status")
+	if err != nil {
+		return nil, fmt.Errorf("error during BMC Status call: %w", err)
+	}
+
+	// The status is reported as {"response":[{"result":[{"uptime":<seconds>,"load":"<1m> <5m> <15m>","mem_total":<bytes>,"mem_free":<bytes>}] }]}
+	// where numbers may also be sent as strings and load as an array
+	result, err := b.objectAPIParseRaw(bodyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing status response: %w", err)
+	}
+
+	var status BMCStatus
+	if raw, ok := result["uptime"]; ok {
+		seconds, err := parseNumber(raw)
+		if err != nil {
+			return nil, fmt.Errorf("uptime: %w", err)
+		}
+		status.Uptime = time.Duration(seconds * float64(time.Second))
+	}
+	if raw, ok := result["load"]; ok {
+		if status.Load, err = parseLoad(raw); err != nil {
+			return nil, fmt.Errorf("load: %w", err)
+		}
+	}
+	for key, field := range map[string]*uint64{"mem_total": &status.MemTotalBytes, "mem_free": &status.MemFreeBytes} {
+		raw, ok := result[key]
+		if !ok {
+			continue
+		}
+		value, err := parseNumber(raw)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("%s: invalid value %s", key, raw)
+		}
+		*field = uint64(value)
+	}
+
+	return &status, nil
+}
+
+// parseNumber converts a JSON number or numeric string into a float64.
+func parseNumber(raw json.RawMessage) (float64, error) {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, fmt.Errorf("invalid number %s: %w", raw, err)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", v)
+		}
+		return number, nil
+	}
+
+	return 0, fmt.Errorf("invalid number %s", raw)
+}
+
+// parseLoad converts load averages given as a string in the format of /proc/loadavg, e.g. "0.10 0.20 0.30",
+// or as a JSON array of three numbers.
+func parseLoad(raw json.RawMessage) ([3]float64, error) {
+	var load [3]float64
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return load, fmt.Errorf("invalid load average %s", raw)
+		}
+		for _, field := range strings.Fields(text) {
+			values = append(values, json.RawMessage(strconv.Quote(field)))
+		}
+	}
+	if len(values) < 3 {
+		return load, fmt.Errorf("invalid load average %s", raw)
+	}
+
+	for i := range load {
+		value, err := parseNumber(values[i])
+		if err != nil {
+			return load, err
+		}
+		load[i] = value
+	}
+
+	return load, nil
+}
@@ -0,0 +1,49 @@
+package bmcapi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_BMCStatus(t *testing.T) {
+	body := `{"response":[{"result":[{"uptime":"3600.5","load":"0.10 0.20 0.30 1/120 4242","mem_total":1048576,"mem_free":"524288"}]}]}`
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "status" {
+			return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	status, err := bmc.BMCStatus()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := BMCStatus{
+		Uptime:        3600*time.Second + 500*time.Millisecond,
+		Load:          [3]float64{0.1, 0.2, 0.3},
+		MemTotalBytes: 1048576,
+		MemFreeBytes:  524288,
+	}
+	if *status != want {
+		t.Errorf("BMCStatus() = %+v, want %+v", *status, want)
+	}
+
+	body = `{"response":[{"result":[{"load":[1,2,3]}]}]}`
+	if status, err := bmc.BMCStatus(); err != nil || status.Load != [3]float64{1, 2, 3} {
+		t.Errorf("BMCStatus() with load array = %+v, %v", status, err)
+	}
+
+	body = `{"response":[{"result":[{"uptime":"soon"}]}]}`
+	if _, err := bmc.BMCStatus(); err == nil {
+		t.Errorf("expected error for an invalid uptime")
+	}
+
+	unsupported := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return mockResponse(http.StatusBadRequest, "Invalid `type` parameter"), nil
+	}))
+	if _, err := unsupported.BMCStatus(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("BMCStatus() error = %v, want ErrUnsupported", err)
+	}
+}
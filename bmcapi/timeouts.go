@@ -0,0 +1,76 @@
+package bmcapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Timeouts are the time limits for a single request to the BMC, by the kind of operation.
+// A zero field uses the default for its kind.
+type Timeouts struct {
+	// Read limits requests that only read state, such as GetPower, and authentication. Default 10 seconds.
+	Read time.Duration
+	// Write limits requests that change state, such as SetPowerResult. Default 30 seconds.
+	Write time.Duration
+	// Flash limits the upload of an image by FlashNodeResult. Default 15 minutes.
+	Flash time.Duration
+}
+
+// defaultTimeouts are the timeouts used when WithTimeouts is not given or leaves a field zero.
+var defaultTimeouts = Timeouts{
+	Read:  10 * time.Second,
+	Write: 30 * time.Second,
+	Flash: 15 * time.Minute,
+}
+
+// WithTimeouts replaces the default time limits of requests, see Timeouts. The limit is applied
+// to each request whose context has no deadline yet, so a deadline on the context passed to a
+// method such as FlashNodeResult overrides it for that call. The Timeout of the http.Client
+// applies on top and should be left zero or set above the flash timeout.
+func WithTimeouts(timeouts Timeouts) Option {
+	return func(b *BMCAPI) error {
+		b.timeouts = timeouts
+		return nil
+	}
+}
+
+// requestTimeout returns the time limit for req according to its kind of operation.
+func (b *BMCAPI) requestTimeout(req *http.Request) time.Duration {
+	timeout, fallback := b.timeouts.Read, defaultTimeouts.Read
+	if req.Method == "POST" && strings.Contains(req.URL.Path, "/upload/") {
+		timeout, fallback = b.timeouts.Flash, defaultTimeouts.Flash
+	} else if isWriteRequest(req) {
+		timeout, fallback = b.timeouts.Write, defaultTimeouts.Write
+	}
+
+	if timeout <= 0 {
+		return fallback
+	}
+	return timeout
+}
+
+// withRequestTimeout returns req with the time limit for its kind of operation applied, unless its context
+// already has a deadline. The returned cancel function must be called once the response body was read.
+func (b *BMCAPI) withRequestTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	if _, ok := req.Context().Deadline(); ok {
+		return req, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), b.requestTimeout(req))
+	return req.WithContext(ctx), cancel
+}
+
+// cancelOnClose is a response body that releases the request's timeout once it is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,46 @@
+package bmcapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_RequestTimeouts(t *testing.T) {
+	bmc := newMockBMCAPI(nil)
+	if err := WithTimeouts(Timeouts{Write: time.Minute})(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		method string
+		url    string
+		want   time.Duration
+	}{
+		{"GET", "http://mock/api/bmc?opt=get&type=power", 10 * time.Second},
+		{"GET", "http://mock/api/bmc?opt=set&type=usb_boot&node=0", time.Minute},
+		{"POST", "http://mock/api/bmc/upload/42", 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, tt.url, nil)
+		if got := bmc.requestTimeout(req); got != tt.want {
+			t.Errorf("requestTimeout(%s %s) = %v, want %v", tt.method, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestBMCAPI_RequestTimeoutApplied(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}))
+	if err := WithTimeouts(Timeouts{Read: 10 * time.Millisecond})(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := bmc.GetPower(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetPower() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,62 @@
+package bmcapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// certificateHint is the guidance added to TLS certificate errors, which are most often caused by the
+// BMC's self-signed certificate.
+const certificateHint = "the BMC's TLS certificate is not trusted; the stock BMC uses a self-signed certificate, " +
+	"so pass NewInsecureClient() or use WithInsecureTLS, or trust the BMC's certificate in the client's RootCAs"
+
+// warnUnverifiedLocal logs a warning when b verifies TLS certificates of a .local BMC, which is almost always
+// the stock BMC with its self-signed certificate, so every request would fail with a certificate error.
+// It is only a warning, as the certificate may have been replaced by a trusted one.
+func (b *BMCAPI) warnUnverifiedLocal() {
+
+	if b.logger == nil || b.strictTLS || !verifiesSelfSigned(b.Client) {
+		return
+	}
+
+	u, err := url.Parse(b.BaseURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(strings.ToLower(u.Hostname()), ".local") {
+		return
+	}
+
+	b.logger.Warn("client verifies TLS certificates of a .local BMC, requests will fail if it uses its self-signed certificate",
+		slog.String("url", b.BaseURL),
+		slog.String("hint", certificateHint),
+	)
+
+}
+
+// verifiesSelfSigned reports whether client would reject the BMC's self-signed certificate: it verifies
+// certificates against the system roots only. Transports other than *http.Transport are not inspected.
+func verifiesSelfSigned(client *http.Client) bool {
+	switch transport := client.Transport.(type) {
+	case nil:
+		return true
+	case *http.Transport:
+		config := transport.TLSClientConfig
+		return config == nil || (!config.InsecureSkipVerify && config.RootCAs == nil && config.VerifyPeerCertificate == nil)
+	}
+	return false
+}
+
+// explainCertificateError adds certificateHint to err if it is a TLS certificate verification error.
+func explainCertificateError(err error) error {
+	var verifyErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &verifyErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) {
+		return fmt.Errorf("%s: %w", certificateHint, err)
+	}
+	return err
+}
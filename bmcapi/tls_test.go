@@ -0,0 +1,48 @@
+package bmcapi
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWarnUnverifiedLocal(t *testing.T) {
+	tests := []struct {
+		url    string
+		client *http.Client
+		opts   []Option
+		warn   bool
+	}{
+		{"https://turingpi.local", &http.Client{}, nil, true},
+		{"https://turingpi.local", nil, nil, false},
+		{"https://turingpi.local", &http.Client{}, []Option{WithStrictTLS()}, false},
+		{"https://bmc.example.com", &http.Client{}, nil, false},
+		{"http://turingpi.local", &http.Client{}, nil, false},
+	}
+	for _, tt := range tests {
+		var logs bytes.Buffer
+		opts := append([]Option{WithLazyAuth(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil)))}, tt.opts...)
+		if _, err := NewBMCAPI(tt.url, "basic", "user", "pass", tt.client, opts...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if warned := strings.Contains(logs.String(), "level=WARN"); warned != tt.warn {
+			t.Errorf("%s with %d options: warned = %v, want %v", tt.url, len(tt.opts), warned, tt.warn)
+		}
+	}
+}
+
+func TestExplainCertificateError(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		return nil, x509.UnknownAuthorityError{}
+	}))
+
+	_, err := bmc.Other()
+	var authorityErr x509.UnknownAuthorityError
+	if !errors.As(err, &authorityErr) || !strings.Contains(err.Error(), "NewInsecureClient") {
+		t.Errorf("Other() error = %v, want the certificate error with a hint", err)
+	}
+}
@@ -0,0 +1,370 @@
+package bmcapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultRebootCommand is the command RebootNodeOS sends when WithRebootCommand is not used.
+const defaultRebootCommand = "reboot"
+
+// bmcUARTAPIResponse is a struct that represents the response from the BMC API for a UART read.
+// It expects the response to be in the format {"response":[{"uart":"<text>" }]}
+type bmcUARTAPIResponse struct {
+	Response []struct {
+		UART string `json:"uart"`
+	} `json:"response"`
+}
+
+// GetUART reads the serial console buffer of the specified node (0-3).
+// The firmware returns its whole buffer on every call, not only the output produced since the last read.
+func (b *BMCAPI) GetUART(node int) (string, error) {
+	return b.getUART(context.Background(), node)
+}
+
+// getUART is GetUART with the request made under ctx.
+func (b *BMCAPI) getUART(ctx context.Context, node int) (string, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return "", ErrInvalidNode
+	}
+
+	bodyBytes, err := b.bmcAPICallContext(ctx, "/api/bmc?opt=get&type=uart&node="+Node(node).queryValue())
+	if err != nil {
+		return "", fmt.Errorf("error during Get UART call: %w", err)
+	}
+
+	var parsed bmcUARTAPIResponse
+
+	if err := unmarshalResponse(bodyBytes, &parsed); err != nil {
+		return "", fmt.Errorf("error parsing json in uart response: %w", err)
+	}
+	if len(parsed.Response) == 0 {
+		return "", fmt.Errorf("no data in response")
+	}
+
+	return parsed.Response[0].UART, nil
+}
+
+// GetUARTAll reads the serial console buffers of all nodes concurrently and returns them keyed by node (0-3).
+// A node whose buffer cannot be read is left out of the map, and its error is included in the returned error,
+// so the buffers of the other nodes are still returned.
+func (b *BMCAPI) GetUARTAll() (map[int]string, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	buffers := make(map[int]string, 4)
+
+	for node := 0; node < 4; node++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text, err := b.GetUART(node)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("node %d: %w", node, err))
+				return
+			}
+			buffers[node] = text
+		}()
+	}
+	wg.Wait()
+
+	return buffers, errors.Join(errs...)
+}
+
+// SetUART writes cmd to the serial console of the specified node (0-3).
+//
+// Deprecated: Use SetUARTResult, which returns a SetResult.
+func (b *BMCAPI) SetUART(node int, cmd string) (*string, error) {
+	return resultString(b.SetUARTResult(node, cmd))
+}
+
+// SetUARTResult writes cmd to the serial console of the specified node (0-3).
+func (b *BMCAPI) SetUARTResult(node int, cmd string) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+
+	bodyBytes, err := b.bmcAPICall("/api/bmc?opt=set&type=uart&node=" + Node(node).queryValue() + "&cmd=" + url.QueryEscape(cmd))
+	if err != nil {
+		return SetResult{}, fmt.Errorf("error during Set UART call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
+
+// SetUARTBytes writes data byte for byte to the serial console of the specified node (0-3), e.g. a file
+// transfer over the console. Data that is valid UTF-8 without NUL bytes is sent like SetUARTResult, whose
+// query encoding keeps every byte intact. Other data cannot be carried by the firmware's text command, so it
+// is sent as the body of a POST request to a raw UART endpoint instead; firmware without one returns ErrUnsupported.
+func (b *BMCAPI) SetUARTBytes(node int, data []byte) (SetResult, error) {
+	// Validate node number
+	if !Node(node).Valid() {
+		return SetResult{}, ErrInvalidNode
+	}
+	if len(data) == 0 {
+		return SetResult{}, fmt.Errorf("data must not be empty")
+	}
+
+	if utf8.Valid(data) && bytes.IndexByte(data, 0) < 0 {
+		return b.SetUARTResult(node, string(data))
+	}
+
+	bodyBytes, _, err := b.bmcAPIRequest(context.Background(), "POST", "/api/bmc?opt=set&type=uart_raw&node="+Node(node).queryValue(), "application/octet-stream", data)
+	if err := capabilityError("raw UART", err); err != nil {
+		return SetResult{}, fmt.Errorf("error during Set UART Bytes call: %w", err)
+	}
+
+	return b.resultAPIParse(bodyBytes)
+}
+
+// RebootNodeOS asks the operating system of the specified node (0-3) to restart cleanly by sending
+// a reboot command over its serial console, instead of cutting power. The command is "reboot" unless
+// changed with WithRebootCommand. It only has an effect if a shell with sufficient privileges is
+// running on the console; the BMC cannot confirm whether the node acted on it.
+func (b *BMCAPI) RebootNodeOS(node int) error {
+	cmd := b.rebootCommand
+	if cmd == "" {
+		cmd = defaultRebootCommand
+	}
+
+	if _, err := b.SetUARTResult(node, cmd); err != nil {
+		return fmt.Errorf("error sending reboot command to node %d: %w", node, err)
+	}
+
+	return nil
+}
+
+// GetUARTSince returns the console output of the specified node (0-3) that follows offset,
+// together with the offset to pass on the next call. Start with an offset of 0.
+//
+// The firmware has no offset parameter, so the diffing is done client side: the whole buffer
+// is fetched and only the part after offset is returned. If the buffer is shorter than offset
+// (e.g. the BMC was restarted) the whole buffer is returned as new output.
+//
+// The firmware keeps a buffer of fixed size and drops the oldest output once it is full. From then on the
+// buffer no longer grows, so an offset cannot tell new output apart and GetUARTSince returns none of it.
+// StreamUART and WaitForNodeConsole recognize the output they have seen instead and are not affected.
+func (b *BMCAPI) GetUARTSince(node int, offset int) (string, int, error) {
+	return b.getUARTSince(context.Background(), node, offset)
+}
+
+// getUARTSince is GetUARTSince with the request made under ctx.
+func (b *BMCAPI) getUARTSince(ctx context.Context, node int, offset int) (string, int, error) {
+	// Validate offset
+	if offset < 0 {
+		return "", 0, fmt.Errorf("offset must not be negative")
+	}
+
+	text, err := b.getUART(ctx, node)
+	if err != nil {
+		return "", offset, err
+	}
+
+	if offset > len(text) {
+		offset = 0
+	}
+
+	return text[offset:], len(text), nil
+}
+
+// consoleTailLength is how many bytes of the console buffer a consoleCursor remembers to find its place again.
+const consoleTailLength = 256
+
+// minConsoleOverlap is the fewest bytes of remembered output a consoleCursor must find at the start of a rolled
+// over buffer, so a buffer that started over is not mistaken for one that kept a few bytes of old output.
+const minConsoleOverlap = 8
+
+// consoleCursor tracks how far the console buffer of a node has been read. Unlike an offset it also works once the
+// firmware's buffer is full and drops old output as new output arrives: the end of the output read last, its tail,
+// is looked up in the new buffer, and everything after it is new. Output that only repeats itself, like the same
+// line over and over, leaves a full buffer looking unchanged and cannot be told apart.
+type consoleCursor struct {
+	offset int
+	tail   string
+}
+
+// advance returns the part of the console buffer text that was not seen before and moves c past it.
+func (c *consoleCursor) advance(text string) string {
+	start := 0
+	if c.offset <= len(text) && strings.HasSuffix(text[:c.offset], c.tail) {
+		// The buffer grew, or did not change
+		start = c.offset
+	} else if i := strings.LastIndex(text[:min(c.offset, len(text))], c.tail); c.tail != "" && i >= 0 {
+		// Old output was dropped from the front of a full buffer, moving the whole tail forward
+		start = i + len(c.tail)
+	} else {
+		// So much old output was dropped that the buffer starts within the tail
+		for n := len(c.tail) - 1; n >= min(minConsoleOverlap, len(c.tail)) && n > 0; n-- {
+			if strings.HasPrefix(text, c.tail[len(c.tail)-n:]) {
+				start = n
+				break
+			}
+		}
+	}
+
+	c.offset = len(text)
+	c.tail = text[max(0, len(text)-consoleTailLength):]
+	return text[start:]
+}
+
+// readConsole is a helper function that reads the console buffer of node and returns the output cursor has not seen.
+func (b *BMCAPI) readConsole(ctx context.Context, node int, cursor *consoleCursor) (string, error) {
+
+	text, err := b.getUART(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	return cursor.advance(text), nil
+
+}
+
+// WaitForNodeConsole polls the serial console of the specified node (0-3) until expect appears in output
+// produced after the call, e.g. "login:" after powering the node on, or until timeout or ctx expires.
+// It returns the console output seen while waiting, also when it fails, to help debug a boot that stalled.
+func (b *BMCAPI) WaitForNodeConsole(ctx context.Context, node int, expect string, timeout time.Duration) (string, error) {
+	return b.waitForNodeConsole(ctx, node, expect, timeout, false)
+}
+
+// WaitForNodeConsoleFold is WaitForNodeConsole with expect matched case-insensitively, e.g. "login:" also matches "Login:".
+func (b *BMCAPI) WaitForNodeConsoleFold(ctx context.Context, node int, expect string, timeout time.Duration) (string, error) {
+	return b.waitForNodeConsole(ctx, node, expect, timeout, true)
+}
+
+// waitForNodeConsole is a helper function for WaitForNodeConsole and WaitForNodeConsoleFold.
+func (b *BMCAPI) waitForNodeConsole(ctx context.Context, node int, expect string, timeout time.Duration, fold bool) (string, error) {
+	// Validate expect
+	if expect == "" {
+		return "", fmt.Errorf("expected console output must not be empty")
+	}
+
+	var cursor consoleCursor
+	if _, err := b.readConsole(ctx, node, &cursor); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if fold {
+		expect = strings.ToLower(expect)
+	}
+	console, err := b.waitForConsole(ctx, node, &cursor, func(text string) bool {
+		if fold {
+			text = strings.ToLower(text)
+		}
+		return strings.Contains(text, expect)
+	})
+	if err != nil {
+		return console, fmt.Errorf("waiting for %q on the console of node %d: %w", expect, node, err)
+	}
+
+	return console, nil
+}
+
+// waitForConsole is a helper function that polls the console of node from cursor on until match reports true
+// for the output seen so far, or ctx expires, and returns that output.
+func (b *BMCAPI) waitForConsole(ctx context.Context, node int, cursor *consoleCursor, match func(string) bool) (string, error) {
+
+	var console strings.Builder
+	err := b.pollUntil(ctx, defaultPollInterval, func() (bool, error) {
+		text, err := b.readConsole(ctx, node, cursor)
+		if err != nil {
+			return false, err
+		}
+		console.WriteString(text)
+		return match(console.String()), nil
+	})
+
+	return console.String(), err
+
+}
+
+// uartStream is the io.ReadCloser returned by StreamUART.
+type uartStream struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close stops the polling goroutine and waits for it to exit.
+func (s *uartStream) Close() error {
+	s.cancel()
+	err := s.PipeReader.Close()
+	<-s.done
+	return err
+}
+
+// StreamUART returns a reader that yields the console output of the specified node (0-3) as it appears,
+// polling the BMC every pollInterval. Output already in the buffer when the stream starts is skipped.
+// The stream ends with io.EOF when ctx is canceled, or with the error of a failed poll.
+// Close must be called to release the polling goroutine.
+func (b *BMCAPI) StreamUART(ctx context.Context, node int, pollInterval time.Duration) (io.ReadCloser, error) {
+	// Validate pollInterval
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("pollInterval must be positive")
+	}
+
+	// Read the current buffer up front so an invalid node or an unreachable BMC fails here rather than mid-stream
+	var cursor consoleCursor
+	if _, err := b.readConsole(ctx, node, &cursor); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	stream := &uartStream{PipeReader: pr, cancel: cancel, done: make(chan struct{})}
+
+	// Closing the writer on cancellation also unblocks a Write nobody is reading
+	context.AfterFunc(ctx, func() { pw.Close() })
+
+	go func() {
+		defer close(stream.done)
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			text, err := b.readConsole(ctx, node, &cursor)
+			if ctx.Err() != nil {
+				// A poll cut short by cancellation ends the stream with io.EOF like any other cancellation
+				pw.Close()
+				return
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if text == "" {
+				continue
+			}
+			// Write blocks until the text is read or the reader is closed
+			if _, err := pw.Write([]byte(text)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return stream, nil
+}
@@ -0,0 +1,344 @@
+package bmcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func uartResponse(text string) string {
+	encoded, _ := json.Marshal(text)
+	return `{"response":[{"uart":` + string(encoded) + `}]}`
+}
+
+func TestBMCAPI_GetUARTSince(t *testing.T) {
+	buffer := "boot\n"
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("type") != "uart" || req.URL.Query().Get("node") != "2" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		return mockResponse(http.StatusOK, uartResponse(buffer)), nil
+	}))
+
+	text, offset, err := bmc.GetUARTSince(2, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "boot\n" || offset != 5 {
+		t.Errorf("first read = %q, %d, want %q, 5", text, offset, "boot\n")
+	}
+
+	buffer += "login: "
+	text, offset, err = bmc.GetUARTSince(2, offset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "login: " || offset != 12 {
+		t.Errorf("second read = %q, %d, want %q, 12", text, offset, "login: ")
+	}
+
+	// A buffer shorter than the offset means it was reset, so everything is new.
+	buffer = "reset\n"
+	text, offset, err = bmc.GetUARTSince(2, offset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "reset\n" || offset != 6 {
+		t.Errorf("read after reset = %q, %d, want %q, 6", text, offset, "reset\n")
+	}
+
+	if _, _, err := bmc.GetUARTSince(4, 0); err == nil {
+		t.Errorf("expected error for invalid node")
+	}
+}
+
+func TestBMCAPI_GetUARTAll(t *testing.T) {
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		node := req.URL.Query().Get("node")
+		if node == "2" {
+			return mockResponse(http.StatusInternalServerError, ""), nil
+		}
+		return mockResponse(http.StatusOK, uartResponse("console "+node)), nil
+	}))
+
+	buffers, err := bmc.GetUARTAll()
+	if err == nil || !strings.Contains(err.Error(), "node 2") {
+		t.Errorf("GetUARTAll() error = %v, want an error for node 2", err)
+	}
+	if len(buffers) != 3 || buffers[0] != "console 0" || buffers[3] != "console 3" {
+		t.Errorf("GetUARTAll() = %v, want the buffers of nodes 0, 1 and 3", buffers)
+	}
+	if _, ok := buffers[2]; ok {
+		t.Errorf("failed node 2 is in the result")
+	}
+}
+
+func TestBMCAPI_StreamUART(t *testing.T) {
+	var mu sync.Mutex
+	buffer := "old output\n"
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		text := buffer
+		buffer += "tick\n"
+		return mockResponse(http.StatusOK, uartResponse(text)), nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := bmc.StreamUART(ctx, 0, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := make([]byte, len("tick\n"))
+	if _, err := io.ReadFull(stream, got); err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if string(got) != "tick\n" {
+		t.Errorf("read %q, want %q", got, "tick\n")
+	}
+
+	cancel()
+	if _, err := io.ReadAll(stream); err != nil {
+		t.Errorf("expected clean EOF after cancel, got %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Errorf("unexpected close error: %v", err)
+	}
+}
+
+func TestBMCAPI_StreamUART_CancelDuringPoll(t *testing.T) {
+	polling := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	calls := 0
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls > 1 {
+			// Hold the poll until the stream was canceled
+			polling <- struct{}{}
+			<-release
+		}
+		return mockResponse(http.StatusOK, uartResponse("output\n")), nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := bmc.StreamUART(ctx, 0, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	<-polling
+	cancel()
+	if _, err := io.ReadAll(stream); err != nil {
+		t.Errorf("expected clean EOF after cancel during a poll, got %v", err)
+	}
+}
+
+func TestBMCAPI_RebootNodeOS(t *testing.T) {
+	var got []string
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		if query.Get("opt") != "set" || query.Get("type") != "uart" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		got = append(got, query.Get("node")+":"+query.Get("cmd"))
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	if err := bmc.RebootNodeOS(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := WithRebootCommand("sudo shutdown -r now")(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := bmc.RebootNodeOS(3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "1:reboot" || got[1] != "3:sudo shutdown -r now" {
+		t.Errorf("UART writes = %q", got)
+	}
+
+	if err := bmc.RebootNodeOS(-1); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("RebootNodeOS(-1) error = %v, want ErrInvalidNode", err)
+	}
+}
+
+func TestBMCAPI_WaitForNodeConsole(t *testing.T) {
+	var reads int
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		// The buffer still holds the prompt of the previous boot, the new prompt follows after the first read
+		reads++
+		console := "old login: \n"
+		if reads%2 == 0 {
+			console += "booting\nturing Login: "
+		}
+		return mockResponse(http.StatusOK, uartResponse(console)), nil
+	}))
+
+	console, err := bmc.WaitForNodeConsoleFold(context.Background(), 1, "login:", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if console != "booting\nturing Login: " {
+		t.Errorf("WaitForNodeConsoleFold() = %q, want the output after the call", console)
+	}
+
+	// Matching is case-sensitive by default, and the output seen is returned on timeout
+	console, err = bmc.WaitForNodeConsole(context.Background(), 1, "login:", 50*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(console, "turing Login: ") {
+		t.Errorf("WaitForNodeConsole() = %q, %v, want the console output with context.DeadlineExceeded", console, err)
+	}
+}
+
+func TestConsoleCursor(t *testing.T) {
+	var cursor consoleCursor
+	steps := []struct {
+		buffer string
+		want   string
+	}{
+		{"boot\n", "boot\n"},
+		{"boot\nlogin: ", "login: "},
+		{"boot\nlogin: ", ""},
+		// The buffer is full: old output is dropped as new output arrives, so its length stays the same
+		{"t\nlogin: root\n", "root\n"},
+		{"gin: root\n$ ls\n", "$ ls\n"},
+		// The BMC restarted and the buffer starts over
+		{"reset\n", "reset\n"},
+	}
+	for _, step := range steps {
+		if got := cursor.advance(step.buffer); got != step.want {
+			t.Errorf("advance(%q) = %q, want %q", step.buffer, got, step.want)
+		}
+	}
+
+	// A tail longer than consoleTailLength is only remembered in part
+	long := strings.Repeat("x", consoleTailLength) + "end\n"
+	cursor = consoleCursor{}
+	cursor.advance(long)
+	if got := cursor.advance(long[10:] + "new\n"); got != "new\n" {
+		t.Errorf("advance() after a long buffer rolled over = %q, want %q", got, "new\n")
+	}
+}
+
+func TestBMCAPI_UART_FullBuffer(t *testing.T) {
+	const size = 32
+	var mu sync.Mutex
+	var output string
+	reads := 0
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		// The buffer holds the last size bytes of output, so it no longer grows; each read sees one more line.
+		// Lines are numbered, as a buffer of identical lines looks the same after each one.
+		mu.Lock()
+		defer mu.Unlock()
+		reads++
+		line := "line " + strconv.Itoa(reads) + "\n"
+		if reads == 2 {
+			line = "login: "
+		}
+		output += line
+		return mockResponse(http.StatusOK, uartResponse(output[len(output)-size:])), nil
+	}))
+	reset := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		output, reads = strings.Repeat(".", size), 0
+	}
+
+	reset()
+	console, err := bmc.WaitForNodeConsole(context.Background(), 1, "login:", time.Second)
+	if err != nil || console != "login: " {
+		t.Errorf("WaitForNodeConsole() with a full buffer = %q, %v, want the prompt", console, err)
+	}
+
+	reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := bmc.StreamUART(ctx, 1, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "login: line 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nline 10\n"
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(stream, got); err != nil || string(got) != want {
+		t.Errorf("StreamUART() with a full buffer read %q, %v, want %q", got, err, want)
+	}
+	stream.Close()
+
+	// An offset cannot follow a full buffer, which GetUARTSince documents
+	reset()
+	_, offset, err := bmc.GetUARTSince(1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text, _, err := bmc.GetUARTSince(1, offset); err != nil || text != "" {
+		t.Errorf("GetUARTSince() on a full buffer = %q, %v, want no output", text, err)
+	}
+}
+
+func TestBMCAPI_SetUARTBytes(t *testing.T) {
+	supported := true
+	var gotCmd string
+	var gotBody [][]byte
+	bmc := newMockBearerBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/bmc/authenticate" {
+			return mockResponse(http.StatusOK, `{"id":"fresh"}`), nil
+		}
+		query := req.URL.Query()
+		switch {
+		case req.Method == "GET" && query.Get("type") == "uart":
+			gotCmd = query.Get("cmd")
+		case !supported:
+			return mockResponse(http.StatusNotFound, ""), nil
+		case req.Method == "POST" && query.Get("type") == "uart_raw" && query.Get("node") == "2":
+			body, _ := io.ReadAll(req.Body)
+			gotBody = append(gotBody, body)
+			if req.Header.Get("Content-Type") != "application/octet-stream" {
+				t.Errorf("Content-Type = %q, want application/octet-stream", req.Header.Get("Content-Type"))
+			}
+			// The first upload is made with the expired session
+			if req.Header.Get("Authorization") != "Bearer fresh" {
+				return mockResponse(http.StatusUnauthorized, ""), nil
+			}
+		default:
+			t.Errorf("unexpected request: %s %s", req.Method, req.URL)
+		}
+		return mockResponse(http.StatusOK, `{"response":[{"result":"ok"}]}`), nil
+	}))
+
+	text := []byte("echo 50% & done\n")
+	if _, err := bmc.SetUARTBytes(2, text); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotCmd != string(text) {
+		t.Errorf("SetUARTBytes() sent cmd %q, want %q", gotCmd, text)
+	}
+
+	binary := []byte{0x01, 0x00, 0xff, 0xfe, '\n'}
+	if result, err := bmc.SetUARTBytes(2, binary); err != nil || !result.Ok() {
+		t.Fatalf("SetUARTBytes() = %+v, %v, want ok", result, err)
+	}
+	if len(gotBody) != 2 || string(gotBody[0]) != string(binary) || string(gotBody[1]) != string(binary) {
+		t.Errorf("SetUARTBytes() sent bodies %q, want %q before and after re-authenticating", gotBody, binary)
+	}
+
+	if _, err := bmc.SetUARTBytes(4, binary); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("SetUARTBytes(4) error = %v, want ErrInvalidNode", err)
+	}
+	supported = false
+	if _, err := bmc.SetUARTBytes(2, binary); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetUARTBytes() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,79 @@
+package bmcapi
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// defaultPollInterval is used by the WaitFor helpers when no poll interval is given.
+const defaultPollInterval = time.Second
+
+// pollJitter randomizes poll intervals by up to fraction in either direction.
+type pollJitter struct {
+	mu       sync.Mutex
+	fraction float64
+	rng      *rand.Rand
+}
+
+// apply returns interval moved by a random amount of up to fraction of it.
+func (j *pollJitter) apply(interval time.Duration) time.Duration {
+	j.mu.Lock()
+	offset := (j.rng.Float64()*2 - 1) * j.fraction
+	j.mu.Unlock()
+
+	return time.Duration(float64(interval) * (1 + offset))
+}
+
+// WithPollJitter randomizes the interval the WaitFor helpers poll at by up to fraction in either direction,
+// e.g. 0.2 for +/- 20%, so many clients started together do not poll the BMC in lockstep.
+// The random numbers are taken from source; pass a seeded source such as rand.NewPCG(1, 2) for
+// reproducible intervals in tests, or nil for a randomly seeded one.
+func WithPollJitter(fraction float64, source rand.Source) Option {
+	return func(b *BMCAPI) error {
+		if fraction < 0 || fraction >= 1 {
+			return fmt.Errorf("jitter fraction must be at least 0 and less than 1")
+		}
+		if source == nil {
+			source = rand.NewPCG(rand.Uint64(), rand.Uint64())
+		}
+		b.jitter = &pollJitter{fraction: fraction, rng: rand.New(source)}
+		return nil
+	}
+}
+
+// pollUntil calls check every interval until it reports done, returns an error, or ctx expires.
+// The first check is made immediately. A non-positive interval uses defaultPollInterval.
+// The interval is jittered when WithPollJitter is used.
+func (b *BMCAPI) pollUntil(ctx context.Context, interval time.Duration, check func() (bool, error)) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		next := interval
+		if b.jitter != nil {
+			next = b.jitter.apply(interval)
+		}
+		timer.Reset(next)
+	}
+}
@@ -0,0 +1,39 @@
+package bmcapi
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestWithPollJitter(t *testing.T) {
+	bmc := &BMCAPI{}
+	if err := WithPollJitter(0.2, rand.NewPCG(1, 2))(bmc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again := &BMCAPI{}
+	if err := WithPollJitter(0.2, rand.NewPCG(1, 2))(again); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	varied := false
+	for i := 0; i < 100; i++ {
+		got := bmc.jitter.apply(time.Second)
+		if got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("jittered interval %v is outside +/- 20%%", got)
+		}
+		if got != time.Second {
+			varied = true
+		}
+		if want := again.jitter.apply(time.Second); got != want {
+			t.Fatalf("same seed gave %v and %v", got, want)
+		}
+	}
+	if !varied {
+		t.Errorf("interval was never jittered")
+	}
+
+	if err := WithPollJitter(1, nil)(bmc); err == nil {
+		t.Errorf("expected error for a jitter fraction of 1")
+	}
+}
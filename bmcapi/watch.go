@@ -0,0 +1,70 @@
+package bmcapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// watchConfirmations is how many polls in a row must report a node's new power state before Watch emits it.
+const watchConfirmations = 2
+
+// Event is a change of a node's power state observed by Watch.
+type Event struct {
+	Node     int
+	State    PowerState
+	Previous PowerState
+	Time     time.Time
+}
+
+// Watch polls the power state of the nodes every interval and sends an Event on the returned channel whenever
+// a node (0-3) is powered on or off, so automation can react to changes without a polling loop of its own.
+// A change is only sent once two polls in a row reported it, so a state that flaps back within one interval
+// is not reported. Failed polls are skipped and it carries on with the next one.
+//
+// The current state is read before Watch returns, and its error is returned if that fails. The channel is
+// closed once ctx is done; the caller must keep receiving from it until then.
+func (b *BMCAPI) Watch(ctx context.Context, interval time.Duration) (<-chan Event, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive")
+	}
+
+	states, err := b.powerStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		var seen [len(states)]int
+		b.pollUntil(ctx, interval, func() (bool, error) {
+			current, err := b.powerStates(ctx)
+			if err != nil {
+				return false, nil
+			}
+
+			for node, state := range current {
+				if state == states[node] {
+					seen[node] = 0
+					continue
+				}
+				if seen[node]++; seen[node] < watchConfirmations {
+					continue
+				}
+
+				event := Event{Node: node, State: state, Previous: states[node], Time: time.Now()}
+				states[node], seen[node] = state, 0
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return false, ctx.Err()
+				}
+			}
+			return false, nil
+		})
+	}()
+
+	return events, nil
+}
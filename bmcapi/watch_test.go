@@ -0,0 +1,63 @@
+package bmcapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBMCAPI_Watch(t *testing.T) {
+	off := `{"response":[{"result":[{"node1":"0","node2":"0","node3":"0","node4":"0"}]}]}`
+	on := `{"response":[{"result":[{"node1":"0","node2":"1","node3":"0","node4":"0"}]}]}`
+	// Node 1 flaps on for a single poll, then is powered on for good
+	polls := []string{off, off, on, off, off, on, on, on}
+
+	var mu sync.Mutex
+	bmc := newMockBMCAPI(mockTransport(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		body := polls[0]
+		if len(polls) > 1 {
+			polls = polls[1:]
+		}
+		return mockResponse(http.StatusOK, body), nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := bmc.Watch(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Node != 1 || event.State != PowerOn || event.Previous != PowerOff || event.Time.IsZero() {
+			t.Errorf("Watch() sent %+v, want node 1 powered on", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watch did not report the power change")
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("Watch() sent %+v after the only change", event)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Errorf("Watch() sent an event after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watch did not close the channel after cancel")
+	}
+
+	if _, err := bmc.Watch(context.Background(), 0); err == nil {
+		t.Errorf("expected error for a zero interval")
+	}
+}
@@ -10,23 +10,7 @@ import (
 )
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: program <username> <password>")
-		return
-	}
-	username := os.Args[1]
-	password := os.Args[2]
-
-	// Example usage of NewBMCAPI function with bearer auth
-	// Note: The baseURL, authType, username, and password should be replaced with actual values.
-	baseURL := "https://turingpi.local"
-	authType := "bearer"
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // Skip TLS verification for self-signed certs
-		}}
-
-	bmcClient, err := bmcapi.NewBMCAPI(baseURL, authType, username, password, client)
+	bmcClient, err := newClient(os.Args[1:])
 	if err != nil {
 		fmt.Println("Error creating BMCAPI:", err)
 		return
@@ -46,21 +30,21 @@ func main() {
 	fmt.Println("MAC:", otherInfo.MAC)
 	fmt.Println("Version:", otherInfo.Version)
 
-	Response, err := bmcClient.USBBoot(1)
+	Response, err := bmcClient.USBBootResult(1)
 	if err != nil {
 		fmt.Println("Error setting USB boot:", err)
 		return
 	}
 
-	fmt.Println("USB Boot Response:", *Response)
+	fmt.Println("USB Boot Response:", Response)
 
-	Response, err = bmcClient.ClearUSBBoot(1)
+	Response, err = bmcClient.ClearUSBBootResult(1)
 	if err != nil {
 		fmt.Println("Error clearing USB boot:", err)
 		return
 	}
 
-	fmt.Println("Clear USB Boot Response:", *Response)
+	fmt.Println("Clear USB Boot Response:", Response)
 
 	powerStatus, err := bmcClient.GetPower()
 	if err != nil {
@@ -73,3 +57,31 @@ func main() {
 	}
 
 }
+
+// newClient creates the client from a config file (see bmcapi.ConfigFromFile) or from a username and password.
+func newClient(args []string) (*bmcapi.BMCAPI, error) {
+	if len(args) == 1 {
+		config, err := bmcapi.ConfigFromFile(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return config.NewBMCAPI(nil)
+	}
+
+	if len(args) < 2 {
+		return nil, fmt.Errorf("usage: program <config.json> | program <username> <password>")
+	}
+	username := args[0]
+	password := args[1]
+
+	// Example usage of NewBMCAPI function with bearer auth
+	// Note: The baseURL, authType, username, and password should be replaced with actual values.
+	baseURL := "https://turingpi.local"
+	authType := "bearer"
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // Skip TLS verification for self-signed certs
+		}}
+
+	return bmcapi.NewBMCAPI(baseURL, authType, username, password, client)
+}